@@ -0,0 +1,56 @@
+package parallel
+
+import (
+	"sync/atomic"
+)
+
+//TestingT is the part of *testing.T used by [WithMaxGoroutineAssertion]
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+//WithMaxGoroutineAssertion fails t if more than n iterations of the call
+//ever run at the same time, so a test can lock in the concurrency of a call
+//and catch regressions when its options change. the failure is reported once per call
+//
+// func TestImport(t *testing.T) {
+// 		parallel.ForWithOptions(ctx, 0, len(rows), insert,
+// 			parallel.WithWorkers(4), parallel.WithMaxGoroutineAssertion(4, t))
+// }
+func WithMaxGoroutineAssertion(n int, t TestingT) Option {
+	return func(c *config) {
+		c.maxGoroutines = &goroutineAssertion{max: n, t: t}
+	}
+}
+
+//goroutineAssertion is the configuration of WithMaxGoroutineAssertion
+type goroutineAssertion struct {
+	max int
+	t   TestingT
+}
+
+//goroutineCounter counts the running iterations of a loop
+type goroutineCounter struct {
+	assertion *goroutineAssertion
+	running   int64
+	failed    int32
+}
+
+func newGoroutineCounter(a *goroutineAssertion) *goroutineCounter {
+	if a == nil {
+		return nil
+	}
+	return &goroutineCounter{assertion: a}
+}
+
+//enter counts an iteration that starts and returns the function that counts its end
+func (c *goroutineCounter) enter() func() {
+	if n := atomic.AddInt64(&c.running, 1); n > int64(c.assertion.max) && atomic.CompareAndSwapInt32(&c.failed, 0, 1) {
+		c.assertion.t.Helper()
+		c.assertion.t.Errorf("parallel: %d goroutines are running, but the maximum is %d", n, c.assertion.max)
+	}
+	return func() {
+		atomic.AddInt64(&c.running, -1)
+	}
+}
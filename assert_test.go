@@ -0,0 +1,37 @@
+package parallel_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+type recordT struct {
+	errors []string
+}
+
+func (t *recordT) Helper() {}
+
+func (t *recordT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestWithMaxGoroutineAssertion(t *testing.T) {
+	parallel.ForWithOptions(context.Background(), 0, 20, func(i int) {
+		time.Sleep(time.Millisecond)
+	}, parallel.WithWorkers(3), parallel.WithMaxGoroutineAssertion(3, t))
+}
+
+func TestWithMaxGoroutineAssertionFail(t *testing.T) {
+	rt := &recordT{}
+	parallel.ForWithOptions(context.Background(), 0, 20, func(i int) {
+		time.Sleep(10 * time.Millisecond)
+	}, parallel.WithMaxGoroutineAssertion(3, rt))
+
+	if len(rt.errors) != 1 {
+		t.Error("require a single failure", rt.errors)
+	}
+}
@@ -0,0 +1,159 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+//Job is a group of functions started by [AllAsync] or [RaceAsync].
+//it is finished when all of its functions returned
+type Job struct {
+	done chan struct{}
+	err  error
+
+	//active holds the *activeIteration of each function that is running, by goroutine id
+	active sync.Map
+}
+
+//AllAsync starts functions in parallel and returns without waiting for them.
+//the context passed to the functions is canceled by the returned CancelFunc or when ctx is done,
+//so a request can start background work, keep the Job and cancel or wait for it later.
+//the error of the Job holds the *PanicError of each function that panicked,
+//or it is the error of the context if the Job was canceled.
+//the CancelFunc must be called to release the resources of the Job.
+//if ctx belongs to a [Scope], the functions belong to it
+//
+// job, cancel := parallel.AllAsync(r.Context(), warmCache, sendAudit)
+// defer cancel()
+// ...
+// err := job.Wait()
+func AllAsync(ctx context.Context, functions ...ContextTaskFunc) (*Job, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	job := &Job{done: make(chan struct{})}
+	errs := newErrorCollector("task", &config{})
+	wg := startAsync(ctx, job, functions, func(i int, err error) {
+		errs.add(i, nil, err)
+	})
+
+	go func() {
+		defer close(job.done)
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			job.err = err
+			return
+		}
+		job.err = errs.err()
+	}()
+	return job, cancel
+}
+
+//RaceAsync starts functions in parallel and returns without waiting for them.
+//when one of them returns without panicking, the context passed to the others is canceled
+//and the error of the Job is nil.
+//the returned CancelFunc cancels all of them, like ctx does.
+//if no function won, the error of the Job is the error of the context,
+//or holds the *PanicError of each function.
+//the CancelFunc must be called to release the resources of the Job.
+//if ctx belongs to a [Scope], the functions belong to it
+//
+// job, cancel := parallel.RaceAsync(ctx, func(ctx context.Context) {
+// 		fetch(ctx, mirror1)
+// }, func(ctx context.Context) {
+// 		fetch(ctx, mirror2)
+// })
+// defer cancel()
+// <-job.Done()
+func RaceAsync(ctx context.Context, functions ...ContextTaskFunc) (*Job, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	raceCtx, endRace := context.WithCancel(ctx)
+	job := &Job{done: make(chan struct{})}
+	errs := newErrorCollector("task", &config{})
+	var won int32
+	wg := startAsync(raceCtx, job, functions, func(i int, err error) {
+		if err != nil {
+			errs.add(i, nil, err)
+			return
+		}
+		if atomic.CompareAndSwapInt32(&won, 0, 1) {
+			endRace()
+		}
+	})
+
+	go func() {
+		defer close(job.done)
+		defer endRace()
+		wg.Wait()
+		switch {
+		case atomic.LoadInt32(&won) == 1 || len(functions) == 0:
+		case ctx.Err() != nil:
+			job.err = ctx.Err()
+		default:
+			job.err = errs.err()
+		}
+	}()
+	return job, cancel
+}
+
+//startAsync calls each of functions with ctx in a new goroutine, that belongs to the Scope of ctx,
+//and calls finished with the index and the *PanicError, or nil, when it returns.
+//the functions are registered in job while they run
+func startAsync(ctx context.Context, job *Job, functions []ContextTaskFunc, finished func(i int, err error)) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(len(functions))
+	for i, f := range functions {
+		goAsync(ctx, func() {
+			defer wg.Done()
+			defer trackIteration(&job.active, goroutineID(), nil, -1, i)()
+			var err error
+			defer func() {
+				finished(i, err)
+			}()
+			defer func() {
+				if r := recover(); r != nil {
+					err = newPanicError(r)
+				}
+			}()
+
+			f(ctx)
+		})
+	}
+	return &wg
+}
+
+//Done returns a channel that is closed when all functions of the Job returned
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+//Wait waits for all functions of the Job to return and returns the error of the Job
+func (j *Job) Wait() error {
+	<-j.done
+	return j.err
+}
+
+//DumpStacks writes the stacks of the functions of the Job that are still running,
+//longest running first, like [DumpActive] does for the loops.
+//it does not need [EnableDumpActive]
+//
+// if err := job.WaitWithContext(ctx); err != nil {
+// 		job.DumpStacks(os.Stderr)
+// }
+func (j *Job) DumpStacks(w io.Writer) error {
+	return dumpStacks(w, &j.active, func(it *activeIteration) string {
+		return fmt.Sprintf("task %d", it.index)
+	})
+}
+
+//WaitWithContext waits for all functions of the Job to return and returns the error of the Job.
+//if ctx is done first, it returns ctx.Err() and the Job keeps running
+func (j *Job) WaitWithContext(ctx context.Context) error {
+	select {
+	case <-j.done:
+		return j.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
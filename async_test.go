@@ -0,0 +1,151 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestAllAsync(t *testing.T) {
+	var count int32
+	job, cancel := parallel.AllAsync(context.Background(), func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	}, func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	})
+	defer cancel()
+
+	if err := job.Wait(); err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Error(count)
+	}
+}
+
+func TestAllAsyncCancel(t *testing.T) {
+	started := make(chan struct{})
+	job, cancel := parallel.AllAsync(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	})
+
+	<-started
+	select {
+	case <-job.Done():
+		t.Fatal("job finished before cancel")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancel()
+	if err := job.Wait(); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+}
+
+func TestAllAsyncPanic(t *testing.T) {
+	job, cancel := parallel.AllAsync(context.Background(), func(ctx context.Context) {
+		panic("fail")
+	}, func(ctx context.Context) {})
+	defer cancel()
+
+	var p *parallel.PanicError
+	if err := job.Wait(); !errors.As(err, &p) {
+		t.Error(err)
+	}
+}
+
+func TestRaceAsync(t *testing.T) {
+	var canceled int32
+	job, cancel := parallel.RaceAsync(context.Background(), func(ctx context.Context) {
+		panic("fail")
+	}, func(ctx context.Context) {
+	}, func(ctx context.Context) {
+		<-ctx.Done()
+		atomic.AddInt32(&canceled, 1)
+	})
+	defer cancel()
+
+	if err := job.Wait(); err != nil {
+		t.Error(err)
+	}
+	if canceled != 1 {
+		t.Error(canceled)
+	}
+}
+
+func TestRaceAsyncCancel(t *testing.T) {
+	job, cancel := parallel.RaceAsync(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		panic("canceled")
+	}, func(ctx context.Context) {
+		<-ctx.Done()
+		panic("canceled")
+	})
+
+	ctx, stop := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+	if err := job.WaitWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error(err)
+	}
+	cancel()
+	if err := job.Wait(); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+
+	job, cancel = parallel.RaceAsync(context.Background())
+	defer cancel()
+	if err := job.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJobDumpStacks(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	job, cancel := parallel.AllAsync(context.Background(), func(ctx context.Context) {
+		close(finished)
+	}, func(ctx context.Context) {
+		started <- struct{}{}
+		<-release
+	})
+	defer cancel()
+	other, cancelOther := parallel.AllAsync(context.Background(), func(ctx context.Context) {
+		started <- struct{}{}
+		<-release
+	})
+	defer cancelOther()
+
+	<-started
+	<-started
+	<-finished
+	//task 0 is unregistered just after it returns
+	var buf bytes.Buffer
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		buf.Reset()
+		if err := job.DumpStacks(&buf); err != nil {
+			t.Error(err)
+		}
+		if !strings.Contains(buf.String(), "task 0 ") {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	job.Wait()
+	other.Wait()
+
+	out := buf.String()
+	if !strings.Contains(out, "task 1 running for") || !strings.Contains(out, "TestJobDumpStacks") {
+		t.Error("require the stack of task 1", out)
+	}
+	if strings.Contains(out, "task 0 ") {
+		t.Error("require only the running functions of the job", out)
+	}
+}
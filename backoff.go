@@ -0,0 +1,125 @@
+package parallel
+
+import (
+	"time"
+)
+
+//Backoff decides how long to wait before the next attempt.
+//attempt is the number of attempts already made, starting with 1
+//elapsed is the time since the first attempt started
+//if ok is false, no more attempts are made.
+type Backoff interface {
+	Next(attempt int, elapsed time.Duration) (delay time.Duration, ok bool)
+}
+
+//BackoffFunc is an adapter to use a function as Backoff
+type BackoffFunc func(attempt int, elapsed time.Duration) (time.Duration, bool)
+
+//Next calls f(attempt, elapsed)
+func (f BackoffFunc) Next(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	return f(attempt, elapsed)
+}
+
+//Constant waits d before every attempt
+func Constant(d time.Duration) Backoff {
+	return BackoffFunc(func(int, time.Duration) (time.Duration, bool) {
+		return d, true
+	})
+}
+
+//Linear waits initial before the second attempt
+//and step longer before each attempt after that
+func Linear(initial time.Duration, step time.Duration) Backoff {
+	return BackoffFunc(func(attempt int, _ time.Duration) (time.Duration, bool) {
+		return initial + time.Duration(attempt-1)*step, true
+	})
+}
+
+//Exponential waits initial before the second attempt
+//and doubles the delay before each attempt after that.
+//the delay never exceeds max. if max <= 0, there is no limit
+func Exponential(initial time.Duration, max time.Duration) Backoff {
+	return BackoffFunc(func(attempt int, _ time.Duration) (time.Duration, bool) {
+		d := initial
+		for i := 1; i < attempt; i++ {
+			if max > 0 && d >= max {
+				break
+			}
+			if d >= time.Duration(1<<62) {
+				//overflow
+				break
+			}
+			d *= 2
+		}
+		if max > 0 && d > max {
+			d = max
+		}
+		return d, true
+	})
+}
+
+//WithJitter randomly shortens each delay of b by up to fraction of it.
+//fraction is in [0, 1]
+//
+// parallel.WithJitter(parallel.Exponential(time.Millisecond, time.Second), 0.5)
+func WithJitter(b Backoff, fraction float64) Backoff {
+	if fraction < 0 || fraction > 1 {
+		panic("jitter fraction must be in [0, 1]")
+	}
+	return &jitterBackoff{b: b, fraction: fraction}
+}
+
+type jitterBackoff struct {
+	b        Backoff
+	fraction float64
+	rand     *lockedRand
+}
+
+func (j *jitterBackoff) Next(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	d, ok := j.b.Next(attempt, elapsed)
+	if !ok {
+		return d, ok
+	}
+	return d - time.Duration(j.rand.Float64()*j.fraction*float64(d)), true
+}
+
+func (j *jitterBackoff) withRand(r *lockedRand) Backoff {
+	return &jitterBackoff{b: bindRand(j.b, r), fraction: j.fraction, rand: r}
+}
+
+//WithMaxElapsed stops b when the next attempt would start
+//later than max after the first attempt.
+func WithMaxElapsed(b Backoff, max time.Duration) Backoff {
+	return &maxElapsedBackoff{b: b, max: max}
+}
+
+type maxElapsedBackoff struct {
+	b   Backoff
+	max time.Duration
+}
+
+func (m *maxElapsedBackoff) Next(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	d, ok := m.b.Next(attempt, elapsed)
+	if !ok || elapsed+d > m.max {
+		return 0, false
+	}
+	return d, true
+}
+
+func (m *maxElapsedBackoff) withRand(r *lockedRand) Backoff {
+	return &maxElapsedBackoff{b: bindRand(m.b, r), max: m.max}
+}
+
+//randBackoff is implemented by backoffs that use random numbers
+//or wrap other backoffs
+type randBackoff interface {
+	withRand(r *lockedRand) Backoff
+}
+
+//bindRand returns b that uses r instead of the global random source
+func bindRand(b Backoff, r *lockedRand) Backoff {
+	if rb, ok := b.(randBackoff); ok && r != nil {
+		return rb.withRand(r)
+	}
+	return b
+}
@@ -0,0 +1,64 @@
+package parallel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestBackoffConstant(t *testing.T) {
+	b := parallel.Constant(10 * time.Millisecond)
+	for i := 1; i < 5; i++ {
+		if d, ok := b.Next(i, 0); !ok || d != 10*time.Millisecond {
+			t.Error("require 10ms", d)
+		}
+	}
+}
+
+func TestBackoffLinear(t *testing.T) {
+	b := parallel.Linear(10*time.Millisecond, 5*time.Millisecond)
+	expected := []time.Duration{10, 15, 20, 25}
+	for i, e := range expected {
+		if d, _ := b.Next(i+1, 0); d != e*time.Millisecond {
+			t.Error("require", e, "but", d)
+		}
+	}
+}
+
+func TestBackoffExponential(t *testing.T) {
+	b := parallel.Exponential(10*time.Millisecond, 50*time.Millisecond)
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for i, e := range expected {
+		if d, _ := b.Next(i+1, 0); d != e*time.Millisecond {
+			t.Error("require", e, "but", d)
+		}
+	}
+
+	if d, _ := parallel.Exponential(time.Second, 0).Next(200, 0); d <= 0 {
+		t.Error("overflow", d)
+	}
+	if d, _ := parallel.Exponential(time.Nanosecond, 0).Next(100, 0); d <= 0 {
+		t.Error("overflow of a power of 2", d)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := parallel.WithJitter(parallel.Constant(100*time.Millisecond), 0.5)
+	for i := 1; i < 100; i++ {
+		d, ok := b.Next(i, 0)
+		if !ok || d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Error("require [50ms, 100ms]", d)
+		}
+	}
+}
+
+func TestBackoffMaxElapsed(t *testing.T) {
+	b := parallel.WithMaxElapsed(parallel.Constant(100*time.Millisecond), time.Second)
+	if _, ok := b.Next(1, 500*time.Millisecond); !ok {
+		t.Error("require next")
+	}
+	if _, ok := b.Next(2, 950*time.Millisecond); ok {
+		t.Error("require stop")
+	}
+}
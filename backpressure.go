@@ -0,0 +1,80 @@
+package parallel
+
+import (
+	"context"
+)
+
+//WithAdaptiveBackpressure lowers the concurrency of the loop when the iterations
+//return errors that classify reports as throttling, such as HTTP 429 or 503.
+//each throttling error halves the number of iterations that may run at once, down to 1,
+//and the number grows back by 1 after as many successes as iterations may run at once.
+//it applies to the functions whose iterations return errors, such as [ForErr].
+//classify sees the error after [WithErrorMapper] and [WithIgnoreErrors]
+//
+// err := parallel.ForErr(ctx, 0, len(ids), fetch, parallel.WithAdaptiveBackpressure(func(err error) bool {
+// 		return errors.Is(err, ErrTooManyRequests)
+// }))
+func WithAdaptiveBackpressure(classify func(error) bool) Option {
+	return func(c *config) {
+		c.throttled = classify
+	}
+}
+
+//backpressure is an adaptive limit of the running iterations of a loop.
+//it is decreased by half on throttling and increased by one on a round of successes
+type backpressure struct {
+	classify func(error) bool
+	state    waitState
+	max      int
+	limit    int
+	running  int
+
+	//successes is the number of successes since the limit changed
+	successes int
+}
+
+func newBackpressure(classify func(error) bool, max int) *backpressure {
+	if max < 1 {
+		max = 1
+	}
+	return &backpressure{
+		classify: classify,
+		max:      max,
+		limit:    max,
+	}
+}
+
+//acquire waits until an iteration may run.
+//it returns false if ctx is done first
+func (b *backpressure) acquire(ctx context.Context) bool {
+	return b.state.acquire(ctx, func() bool {
+		if b.running >= b.limit {
+			return false
+		}
+		b.running++
+		return true
+	}) == nil
+}
+
+//release ends an iteration that returned err and adjusts the limit
+func (b *backpressure) release(err error) {
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+
+	b.running--
+	switch {
+	case err != nil && b.classify(err):
+		b.limit /= 2
+		if b.limit < 1 {
+			b.limit = 1
+		}
+		b.successes = 0
+	case err == nil && b.limit < b.max:
+		b.successes++
+		if b.successes >= b.limit {
+			b.limit++
+			b.successes = 0
+		}
+	}
+	b.state.notify()
+}
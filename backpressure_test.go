@@ -0,0 +1,35 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithAdaptiveBackpressure(t *testing.T) {
+	errThrottled := errors.New("429")
+	var running, throttled int32
+	parallel.ForErr(context.Background(), 0, 200, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		//the backend throttles when more than 2 calls run at once
+		time.Sleep(time.Millisecond)
+		if n > 2 {
+			atomic.AddInt32(&throttled, 1)
+			return errThrottled
+		}
+		return nil
+	}, parallel.WithWorkers(16), parallel.WithAdaptiveBackpressure(func(err error) bool {
+		return errors.Is(err, errThrottled)
+	}))
+
+	//without backpressure, 14 of every 16 calls are throttled
+	if throttled > 100 {
+		t.Error("require fewer throttled calls", throttled)
+	}
+}
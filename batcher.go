@@ -0,0 +1,128 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//ErrKeyNotLoaded is returned by [Batcher.Load] when the load function
+//did not return a value for the key
+var ErrKeyNotLoaded = errors.New("parallel: key not loaded")
+
+//Batcher coalesces the keys requested by concurrent Load calls
+//into a single call of the load function.
+//a batch is loaded when it has maxBatch keys or maxWait has passed since its first key.
+//it is safe for concurrent use
+//
+// users := parallel.NewBatcher(100, 5*time.Millisecond, db.GetUsers)
+// parallel.ForContext(ctx, 0, len(posts), func(ctx context.Context, i int) {
+// 		author, err := users.Load(ctx, posts[i].AuthorID)
+// 		...
+// })
+type Batcher[K comparable, V any] struct {
+	maxBatch int
+	maxWait  time.Duration
+	load     func([]K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending *batch[K, V]
+}
+
+//batch is the keys loaded by a single call of the load function
+type batch[K comparable, V any] struct {
+	keys   []K
+	index  map[K]struct{}
+	timer  *time.Timer
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+//NewBatcher creates a Batcher that calls load with at most maxBatch keys.
+//if maxBatch <= 0, the size of a batch is not limited
+func NewBatcher[K comparable, V any](maxBatch int, maxWait time.Duration, load func([]K) (map[K]V, error)) *Batcher[K, V] {
+	return &Batcher[K, V]{
+		maxBatch: maxBatch,
+		maxWait:  maxWait,
+		load:     load,
+	}
+}
+
+//Load adds k to the pending batch and waits for the batch to be loaded.
+//if ctx is done first, it returns ctx.Err(); the batch is still loaded for the other callers
+func (b *Batcher[K, V]) Load(ctx context.Context, k K) (V, error) {
+	bt := b.add(k)
+
+	select {
+	case <-bt.done:
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+
+	if bt.err != nil {
+		var zero V
+		return zero, bt.err
+	}
+	v, ok := bt.values[k]
+	if !ok {
+		return v, ErrKeyNotLoaded
+	}
+	return v, nil
+}
+
+//add puts k in the pending batch and returns the batch
+func (b *Batcher[K, V]) add(k K) *batch[K, V] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt := b.pending
+	if bt == nil {
+		bt = &batch[K, V]{
+			index: make(map[K]struct{}),
+			done:  make(chan struct{}),
+		}
+		b.pending = bt
+		bt.timer = time.AfterFunc(b.maxWait, func() {
+			b.flush(bt)
+		})
+	}
+
+	if _, ok := bt.index[k]; !ok {
+		bt.index[k] = struct{}{}
+		bt.keys = append(bt.keys, k)
+	}
+
+	if b.maxBatch > 0 && len(bt.keys) >= b.maxBatch {
+		b.pending = nil
+		bt.timer.Stop()
+		go b.run(bt)
+	}
+	return bt
+}
+
+//flush loads bt if it is still pending
+func (b *Batcher[K, V]) flush(bt *batch[K, V]) {
+	b.mu.Lock()
+	if b.pending != bt {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+
+	b.run(bt)
+}
+
+//run calls the load function with the keys of bt and wakes up the waiters
+func (b *Batcher[K, V]) run(bt *batch[K, V]) {
+	defer close(bt.done)
+	defer func() {
+		if r := recover(); r != nil {
+			bt.err = newPanicError(r)
+		}
+	}()
+	bt.values, bt.err = b.load(bt.keys)
+}
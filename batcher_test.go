@@ -0,0 +1,64 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestBatcher(t *testing.T) {
+	var calls int32
+	b := parallel.NewBatcher(10, 20*time.Millisecond, func(keys []int) (map[int]int, error) {
+		atomic.AddInt32(&calls, 1)
+		if len(keys) > 10 {
+			t.Error("batch too large", len(keys))
+		}
+		m := make(map[int]int)
+		for _, k := range keys {
+			m[k] = k * 2
+		}
+		return m, nil
+	})
+
+	parallel.ForContext(context.Background(), 0, 100, func(ctx context.Context, i int) {
+		v, err := b.Load(ctx, i%50)
+		if err != nil {
+			t.Error(err)
+		}
+		if v != i%50*2 {
+			t.Error("bad value", i, v)
+		}
+	})
+
+	if n := atomic.LoadInt32(&calls); n >= 100 {
+		t.Error("require batched calls", n)
+	}
+}
+
+func TestBatcherMissingKey(t *testing.T) {
+	b := parallel.NewBatcher(0, time.Millisecond, func(keys []string) (map[string]int, error) {
+		return map[string]int{"a": 1}, nil
+	})
+
+	if v, err := b.Load(context.Background(), "a"); err != nil || v != 1 {
+		t.Error("require 1", v, err)
+	}
+	if _, err := b.Load(context.Background(), "b"); !errors.Is(err, parallel.ErrKeyNotLoaded) {
+		t.Error("require ErrKeyNotLoaded", err)
+	}
+}
+
+func TestBatcherError(t *testing.T) {
+	e := errors.New("down")
+	b := parallel.NewBatcher(1, time.Second, func(keys []int) (map[int]int, error) {
+		return nil, e
+	})
+
+	if _, err := b.Load(context.Background(), 1); err != e {
+		t.Error("require error", err)
+	}
+}
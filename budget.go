@@ -0,0 +1,196 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//ErrBudgetExhausted is returned by [Budget.Spend] when the cost does not fit in the budget
+var ErrBudgetExhausted = errors.New("parallel: budget exhausted")
+
+//BudgetState is the usage of a [Budget] that a [BudgetStore] persists
+type BudgetState struct {
+	//Window is the start of the current window.
+	//it is the zero time if the budget has no period
+	Window time.Time
+
+	//Used is the cost spent in Window
+	Used int
+}
+
+//BudgetStore persists the state of a [Budget] across runs,
+//for example in a file or a database shared by scheduled jobs
+type BudgetStore interface {
+	//Load returns the saved state. a store without a saved state returns the zero BudgetState
+	Load() (BudgetState, error)
+
+	//Save saves s. it is called after each successful Spend.
+	//if Load or Save fails, the loop does not start the iteration and the rest,
+	//and the functions that return errors, such as [ForErr], return the error for the iteration
+	Save(s BudgetState) error
+}
+
+//Budget is a quota of cost shared by calls, such as the daily quota of an external API.
+//it is safe for concurrent use
+type Budget struct {
+	mu        sync.Mutex
+	limit     int
+	period    time.Duration
+	store     BudgetStore
+	loaded    bool
+	state     BudgetState
+	exhausted bool
+	now       func() time.Time
+}
+
+//NewBudget creates a Budget of limit per period.
+//windows start at multiples of period since the zero time in UTC,
+//so 24 * time.Hour resets the budget at midnight UTC.
+//if period is 0, the budget is a total that is never reset.
+//if store is nil, the state is kept only in memory
+func NewBudget(limit int, period time.Duration, store BudgetStore) *Budget {
+	return &Budget{
+		limit:  limit,
+		period: period,
+		store:  store,
+		now:    time.Now,
+	}
+}
+
+//Spend takes cost from the budget.
+//if the cost does not fit in the current window, it returns ErrBudgetExhausted and nothing is taken
+func (b *Budget) Spend(cost int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.sync(); err != nil {
+		return err
+	}
+	if b.state.Used+cost > b.limit {
+		b.exhausted = true
+		return ErrBudgetExhausted
+	}
+
+	b.state.Used += cost
+	if b.store != nil {
+		if err := b.store.Save(b.state); err != nil {
+			b.state.Used -= cost
+			return err
+		}
+	}
+	return nil
+}
+
+//Remaining returns the cost left in the current window
+func (b *Budget) Remaining() (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.sync(); err != nil {
+		return 0, err
+	}
+	return b.limit - b.state.Used, nil
+}
+
+//Exhausted reports whether Spend was refused in the current window
+func (b *Budget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.sync(); err != nil {
+		return false
+	}
+	return b.exhausted
+}
+
+//sync loads the state from the store on the first use
+//and starts a new window if the current one has passed. b.mu must be held
+func (b *Budget) sync() error {
+	if !b.loaded && b.store != nil {
+		s, err := b.store.Load()
+		if err != nil {
+			return err
+		}
+		b.state = s
+	}
+	b.loaded = true
+
+	if b.period > 0 {
+		window := b.now().UTC().Truncate(b.period)
+		if !window.Equal(b.state.Window) {
+			b.state = BudgetState{Window: window}
+			b.exhausted = false
+		}
+	}
+	return nil
+}
+
+//WithBudget spends cost(i) from b before the iteration i starts.
+//when b is exhausted, the rest of the iterations are not started,
+//so the call ends cleanly and the next run continues in the next window.
+//if cost is nil, each iteration costs 1
+//
+// quota := parallel.NewBudget(10000, 24*time.Hour, store)
+// parallel.ForWithOptions(ctx, 0, len(ids), f, parallel.WithBudget(quota, nil))
+// if quota.Exhausted() {
+// 		log.Println("quota exhausted, resume tomorrow")
+// }
+func WithBudget(b *Budget, cost func(i int) int) Option {
+	return func(c *config) {
+		c.budget = &budgetGate{budget: b, cost: cost}
+	}
+}
+
+//budgetGate is the budget of a loop
+type budgetGate struct {
+	budget *Budget
+	cost   func(i int) int
+
+	//index and err are the first iteration that failed because of the BudgetStore and its error
+	mu    sync.Mutex
+	index int
+	err   error
+}
+
+//spend reports whether the iteration i fits in the budget.
+//an error of the BudgetStore is kept and returned by takeStoreErr
+func (g *budgetGate) spend(i int) bool {
+	cost := 1
+	if g.cost != nil {
+		cost = g.cost(i)
+	}
+	err := g.budget.Spend(cost)
+	if err != nil && !errors.Is(err, ErrBudgetExhausted) {
+		g.mu.Lock()
+		if g.err == nil {
+			g.index, g.err = i, err
+		}
+		g.mu.Unlock()
+	}
+	return err == nil
+}
+
+//takeStoreErr returns the iteration that failed because of the BudgetStore and its error,
+//and forgets them
+func (g *budgetGate) takeStoreErr() (index int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	index, err = g.index, g.err
+	g.err = nil
+	return index, err
+}
+
+//admit waits until the iteration it may start.
+//if it returns false, it and the rest are not started
+func (l *loop) admit(ctx context.Context, it int) bool {
+	if l.limiter != nil && l.limiter.Wait(ctx) != nil {
+		//canceled while waiting
+		return false
+	}
+	if l.budget != nil && !l.budget.spend(it) {
+		return false
+	}
+	return true
+}
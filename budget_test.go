@@ -0,0 +1,112 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+type memoryBudgetStore struct {
+	state parallel.BudgetState
+	saves int
+}
+
+func (s *memoryBudgetStore) Load() (parallel.BudgetState, error) {
+	return s.state, nil
+}
+
+func (s *memoryBudgetStore) Save(state parallel.BudgetState) error {
+	s.state = state
+	s.saves++
+	return nil
+}
+
+func TestWithBudget(t *testing.T) {
+	b := parallel.NewBudget(10, 0, nil)
+	var count int32
+	parallel.ForWithOptions(context.Background(), 0, 100, func(i int) {
+		atomic.AddInt32(&count, 1)
+	}, parallel.WithBudget(b, func(i int) int {
+		return 2
+	}))
+
+	if count != 5 {
+		t.Error("require 5", count)
+	}
+	if !b.Exhausted() {
+		t.Error("require exhausted")
+	}
+	if err := b.Spend(1); !errors.Is(err, parallel.ErrBudgetExhausted) {
+		t.Error("require ErrBudgetExhausted", err)
+	}
+}
+
+func TestBudgetStore(t *testing.T) {
+	store := &memoryBudgetStore{}
+	b := parallel.NewBudget(10, 0, store)
+	if err := b.Spend(7); err != nil {
+		t.Fatal(err)
+	}
+
+	//next run
+	b = parallel.NewBudget(10, 0, store)
+	if n, _ := b.Remaining(); n != 3 {
+		t.Error("require 3", n)
+	}
+	if store.saves != 1 {
+		t.Error("require 1 save", store.saves)
+	}
+}
+
+func TestBudgetWindow(t *testing.T) {
+	//exhausted in a window that has passed
+	store := &memoryBudgetStore{state: parallel.BudgetState{
+		Window: time.Now().UTC().Add(-48 * time.Hour).Truncate(24 * time.Hour),
+		Used:   10,
+	}}
+
+	b := parallel.NewBudget(10, 24*time.Hour, store)
+	if n, _ := b.Remaining(); n != 10 {
+		t.Error("require new window", n)
+	}
+}
+
+//failingBudgetStore fails to save after two successful saves
+type failingBudgetStore struct {
+	memoryBudgetStore
+	err error
+}
+
+func (s *failingBudgetStore) Save(state parallel.BudgetState) error {
+	if s.saves >= 2 {
+		return s.err
+	}
+	return s.memoryBudgetStore.Save(state)
+}
+
+func TestWithBudgetStoreError(t *testing.T) {
+	errStore := errors.New("store")
+	b := parallel.NewBudget(10, 0, &failingBudgetStore{err: errStore})
+	var count int32
+	err := parallel.ForErr(context.Background(), 0, 5, func(i int) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}, parallel.WithBudget(b, nil), parallel.WithSequential())
+
+	if !errors.Is(err, errStore) {
+		t.Error("require the error of the store", err)
+	}
+	if err != nil && err.Error() != "iteration 2: store" {
+		t.Error("require the iteration that failed", err)
+	}
+	if count != 2 {
+		t.Error("require 2", count)
+	}
+	if b.Exhausted() {
+		t.Error("the budget is not exhausted")
+	}
+}
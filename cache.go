@@ -0,0 +1,158 @@
+package parallel
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+//Cache stores values by key.
+//implementations must be safe for concurrent use
+type Cache[K comparable, V any] interface {
+	//Get returns the value stored for key
+	Get(key K) (V, bool)
+
+	//Set stores value for key
+	Set(key K, value V)
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+//LRUCache is a Cache that keeps at most cap entries.
+//when full, the least recently used entry is removed
+type LRUCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	cap     int
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+//NewLRUCache creates a LRUCache that holds up to cap entries.
+//cap must be greater than 0
+func NewLRUCache[K comparable, V any](cap int) *LRUCache[K, V] {
+	if cap <= 0 {
+		panic("cache capacity must be greater than 0")
+	}
+	return &LRUCache[K, V]{
+		cap:     cap,
+		order:   list.New(),
+		entries: make(map[K]*list.Element, cap),
+	}
+}
+
+//Get returns the value stored for key and marks it as recently used
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+//Set stores value for key.
+//if the cache is full, the least recently used entry is removed
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+
+	if c.order.Len() >= c.cap {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+//Len returns the number of stored entries
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+//TTLCache is a Cache whose entries expire ttl after they are set
+type TTLCache[K comparable, V any] struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[K]ttlEntry[V]
+	lastSweep time.Time
+}
+
+//NewTTLCache creates a TTLCache.
+//ttl must be greater than 0
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	if ttl <= 0 {
+		panic("cache ttl must be greater than 0")
+	}
+	return &TTLCache[K, V]{
+		ttl:     ttl,
+		entries: make(map[K]ttlEntry[V]),
+	}
+}
+
+//Get returns the value stored for key if it has not expired
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && time.Now().Before(e.expires) {
+		return e.value, true
+	}
+	if ok {
+		delete(c.entries, key)
+	}
+	var zero V
+	return zero, false
+}
+
+//Set stores value for key. it expires after ttl
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	//expired entries that are never read again are swept at most once per ttl
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.removeExpired(now)
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+//Len returns the number of entries that have not expired
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeExpired(time.Now())
+	return len(c.entries)
+}
+
+//removeExpired removes all expired entries.
+//c.mu must be held
+func (c *TTLCache[K, V]) removeExpired(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.lastSweep = now
+}
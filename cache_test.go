@@ -0,0 +1,64 @@
+package parallel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestLRUCacheEvict(t *testing.T) {
+	c := parallel.NewLRUCache[string, int](2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	//a is recently used
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Error("require a = 1")
+	}
+
+	c.Set("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Error("b must be evicted")
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Error("require c = 3")
+	}
+	if c.Len() != 2 {
+		t.Error("require len 2")
+	}
+}
+
+func TestLRUCacheParallel(t *testing.T) {
+	var c parallel.Cache[int, int] = parallel.NewLRUCache[int, int](100)
+	parallel.For(0, 1000, func(i int) {
+		c.Set(i%200, i)
+		c.Get(i % 50)
+	})
+}
+
+func TestLRUCacheBadCap(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("cap 0 must panic")
+		}
+	}()
+	parallel.NewLRUCache[int, int](0)
+}
+
+func TestTTLCacheExpire(t *testing.T) {
+	c := parallel.NewTTLCache[string, int](50 * time.Millisecond)
+	c.Set("a", 1)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Error("require a = 1")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a must be expired")
+	}
+	if c.Len() != 0 {
+		t.Error("require len 0")
+	}
+}
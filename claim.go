@@ -0,0 +1,71 @@
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+//WithClaiming runs the loop in runtime.GOMAXPROCS(0) workers
+//that claim the next index with an atomic counter,
+//instead of starting a goroutine for each index.
+//each index is still called exactly once.
+//it may become the default in a later version
+func WithClaiming() Option {
+	return func(c *config) {
+		c.claiming = true
+	}
+}
+
+//WithLazyDispatch is [WithClaiming].
+//the indices are not generated ahead, so a huge range such as For(0, 50_000_000, f)
+//uses memory and goroutines in proportion to the number of workers, not the range
+func WithLazyDispatch() Option {
+	return WithClaiming()
+}
+
+//WithWorkers is [WithClaiming] with n workers instead of runtime.GOMAXPROCS(0),
+//so at most n iterations run at once.
+//if n <= 0, runtime.GOMAXPROCS(0) is used
+//
+// parallel.ForErr(ctx, 0, len(urls), fetch, parallel.WithWorkers(8))
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.claiming = true
+		c.workers = n
+	}
+}
+
+//doClaimingLoop calls f for each index of l in workers that claim the indices
+func doClaimingLoop(ctx context.Context, l *loop, f workerLoop) {
+	n := int64(l.len())
+	workers := workerCount(l.workers)
+	if int64(workers) > n {
+		workers = int(n)
+	}
+
+	next := int64(-1)
+	lastWorker := int64(-1)
+	startWorkers(workers, func() {
+		id := l.goroutineID()
+		worker := int(atomic.AddInt64(&lastWorker, 1))
+		for {
+			k := atomic.AddInt64(&next, 1)
+			if k >= n {
+				return
+			}
+			it := l.begin + int(k)*l.step
+			if !l.admit(ctx, it) {
+				//the rest are not started
+				atomic.StoreInt64(&next, n)
+				return
+			}
+			if !l.run(ctx, id, worker, f, it) {
+				return
+			}
+			if l.yield {
+				runtime.Gosched()
+			}
+		}
+	}).Wait()
+}
@@ -0,0 +1,102 @@
+package parallel_test
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithClaiming(t *testing.T) {
+	const n = 10000
+	var calls [n]int32
+	var running, maxRunning int32
+	parallel.ForWithOptions(context.Background(), 0, n, func(i int) {
+		r := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if r <= m || atomic.CompareAndSwapInt32(&maxRunning, m, r) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls[i], 1)
+		atomic.AddInt32(&running, -1)
+	}, parallel.WithClaiming())
+
+	for i, c := range calls {
+		if c != 1 {
+			t.Error("require exactly one call", i, c)
+		}
+	}
+	if maxRunning > int32(runtime.GOMAXPROCS(0)) {
+		t.Error("require at most GOMAXPROCS running", maxRunning)
+	}
+}
+
+func TestWithClaimingDescendingPanic(t *testing.T) {
+	var sum int64
+	parallel.ForWithOptions(context.Background(), 10, 0, func(i int) {
+		if i == 5 {
+			panic("hello")
+		}
+		atomic.AddInt64(&sum, int64(i))
+	}, parallel.WithClaiming(), parallel.WithDescending())
+
+	if sum != 50 {
+		t.Error("require 50", sum)
+	}
+}
+
+func TestWithLazyDispatchHugeRange(t *testing.T) {
+	const n = 1000000
+	var count, maxGoroutines int64
+	parallel.ForWithOptions(context.Background(), 0, n, func(i int) {
+		atomic.AddInt64(&count, 1)
+		if i%100000 == 0 {
+			if g := int64(runtime.NumGoroutine()); g > atomic.LoadInt64(&maxGoroutines) {
+				atomic.StoreInt64(&maxGoroutines, g)
+			}
+		}
+	}, parallel.WithLazyDispatch())
+
+	if count != n {
+		t.Error("require", n, count)
+	}
+	if maxGoroutines > 100 {
+		t.Error("require O(workers) goroutines", maxGoroutines)
+	}
+}
+
+func BenchmarkFor(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parallel.For(0, 10000, func(i int) {})
+	}
+}
+
+func BenchmarkForWithClaiming(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parallel.ForWithOptions(context.Background(), 0, 10000, func(i int) {}, parallel.WithClaiming())
+	}
+}
+
+func TestWithWorkers(t *testing.T) {
+	var running, peak int32
+	parallel.ForWithOptions(context.Background(), 0, 50, func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}, parallel.WithWorkers(3))
+
+	if peak > 3 {
+		t.Error("require at most 3", peak)
+	}
+}
@@ -0,0 +1,214 @@
+package parallel
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+//Collector reduces elements of type T to a result of type R.
+//each worker accumulates its part of the input in its own accumulator of type A
+//without locks, and the accumulators are merged at the end
+type Collector[T, A, R any] interface {
+	//New returns an empty accumulator
+	New() A
+
+	//Add adds e to acc and returns acc
+	Add(acc A, e T) A
+
+	//Merge merges b into a and returns a.
+	//b holds the elements after the elements of a
+	Merge(a A, b A) A
+
+	//Finish returns the result of acc
+	Finish(acc A) R
+}
+
+//Collect reduces slice with c in parallel.
+//slice is split into one contiguous part per worker
+//and the accumulators are merged in the order of the parts.
+//if New or Add panics in a worker, the accumulators are not merged
+//and Collect panics with a *PanicError of the first panic
+//
+// sum := parallel.Collect(s, parallel.SumCollector[int]())
+func Collect[T, A, R any](slice []T, c Collector[T, A, R], opts ...Option) R {
+	cfg := newConfig(opts)
+	workers := runtime.GOMAXPROCS(0)
+	if cfg.sequential {
+		workers = 1
+	}
+	if workers > len(slice) {
+		workers = len(slice)
+	}
+	if workers == 0 {
+		return c.Finish(c.New())
+	}
+
+	size := (len(slice) + workers - 1) / workers
+	accs := newResultSlots[A](workers, cfg.paddedResults)
+	var failed atomic.Pointer[PanicError]
+	For(0, workers, func(w int) {
+		defer func() {
+			if r := recover(); r != nil {
+				failed.CompareAndSwap(nil, newPanicError(r))
+			}
+		}()
+
+		acc := c.New()
+		end := (w + 1) * size
+		if end > len(slice) {
+			end = len(slice)
+		}
+		for i := w * size; i < end; i++ {
+			acc = c.Add(acc, slice[i])
+		}
+		accs.set(w, acc)
+	})
+	if p := failed.Load(); p != nil {
+		panic(p)
+	}
+
+	acc := accs.get(0)
+	for w := 1; w < workers; w++ {
+		acc = c.Merge(acc, accs.get(w))
+	}
+	return c.Finish(acc)
+}
+
+//Number is the constraint of the types that [Sum] can add
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+//CollectorFuncs is a Collector made of functions.
+//if FinishFunc is nil, the accumulator must be the result
+type CollectorFuncs[T, A, R any] struct {
+	NewFunc    func() A
+	AddFunc    func(acc A, e T) A
+	MergeFunc  func(a A, b A) A
+	FinishFunc func(acc A) R
+}
+
+//New calls c.NewFunc
+func (c CollectorFuncs[T, A, R]) New() A {
+	return c.NewFunc()
+}
+
+//Add calls c.AddFunc
+func (c CollectorFuncs[T, A, R]) Add(acc A, e T) A {
+	return c.AddFunc(acc, e)
+}
+
+//Merge calls c.MergeFunc
+func (c CollectorFuncs[T, A, R]) Merge(a A, b A) A {
+	return c.MergeFunc(a, b)
+}
+
+//Finish calls c.FinishFunc
+func (c CollectorFuncs[T, A, R]) Finish(acc A) R {
+	if c.FinishFunc == nil {
+		return any(acc).(R)
+	}
+	return c.FinishFunc(acc)
+}
+
+//SumCollector adds the elements
+func SumCollector[T Number]() Collector[T, T, T] {
+	return CollectorFuncs[T, T, T]{
+		NewFunc: func() T {
+			return 0
+		},
+		AddFunc: func(acc T, e T) T {
+			return acc + e
+		},
+		MergeFunc: func(a T, b T) T {
+			return a + b
+		},
+	}
+}
+
+//CountCollector counts the elements that pred is true
+func CountCollector[T any](pred func(T) bool) Collector[T, int, int] {
+	return CollectorFuncs[T, int, int]{
+		NewFunc: func() int {
+			return 0
+		},
+		AddFunc: func(acc int, e T) int {
+			if pred(e) {
+				acc++
+			}
+			return acc
+		},
+		MergeFunc: func(a int, b int) int {
+			return a + b
+		},
+	}
+}
+
+//GroupByCollector groups the elements by keyFn.
+//the elements of each group keep the order of the input
+func GroupByCollector[T any, K comparable](keyFn func(T) K) Collector[T, map[K][]T, map[K][]T] {
+	return CollectorFuncs[T, map[K][]T, map[K][]T]{
+		NewFunc: func() map[K][]T {
+			return make(map[K][]T)
+		},
+		AddFunc: func(acc map[K][]T, e T) map[K][]T {
+			k := keyFn(e)
+			acc[k] = append(acc[k], e)
+			return acc
+		},
+		MergeFunc: func(a map[K][]T, b map[K][]T) map[K][]T {
+			for k, v := range b {
+				a[k] = append(a[k], v...)
+			}
+			return a
+		},
+	}
+}
+
+//Sum adds the elements of slice in parallel
+func Sum[T Number](slice []T) T {
+	return Collect(slice, SumCollector[T]())
+}
+
+//Count counts the elements of slice that pred is true in parallel
+func Count[T any](slice []T, pred func(T) bool) int {
+	return Collect(slice, CountCollector(pred))
+}
+
+//GroupBy groups the elements of slice by keyFn in parallel.
+//the elements of each group keep the order of slice
+//
+// byLen := parallel.GroupBy(words, func(w string) int {
+// 		return len(w)
+// })
+func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
+	return Collect(slice, GroupByCollector(keyFn))
+}
+
+//ForEachAccum calls f for each element of s in parallel
+//with an accumulator that belongs to the worker, so f needs no lock.
+//the accumulators are created by newAccum and merged by merge at the end.
+//f changes a in place, so A is usually a pointer, map or slice header in a struct
+//
+// counts := parallel.ForEachAccum(words, func() map[string]int {
+// 		return map[string]int{}
+// }, func(a map[string]int, w string) {
+// 		a[w]++
+// }, func(a, b map[string]int) map[string]int {
+// 		for k, v := range b {
+// 			a[k] += v
+// 		}
+// 		return a
+// })
+func ForEachAccum[T, A any](s []T, newAccum func() A, f func(a A, e T), merge func(A, A) A) A {
+	return Collect(s, CollectorFuncs[T, A, A]{
+		NewFunc: newAccum,
+		AddFunc: func(acc A, e T) A {
+			f(acc, e)
+			return acc
+		},
+		MergeFunc: merge,
+	})
+}
@@ -0,0 +1,102 @@
+package parallel_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestSum(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	if sum := parallel.Sum(s); sum != 499500 {
+		t.Error("require 499500", sum)
+	}
+	if sum := parallel.Sum([]float64{}); sum != 0 {
+		t.Error("require 0", sum)
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	if c := parallel.Count(s, func(e int) bool { return e%2 == 0 }); c != 3 {
+		t.Error("require 3", c)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := strings.Fields("a bb cc d eee ff g")
+	groups := parallel.GroupBy(words, func(w string) int {
+		return len(w)
+	})
+
+	expect := map[int][]string{
+		1: {"a", "d", "g"},
+		2: {"bb", "cc", "ff"},
+		3: {"eee"},
+	}
+	if !reflect.DeepEqual(groups, expect) {
+		t.Error("require", expect, groups)
+	}
+}
+
+func TestCollectCustom(t *testing.T) {
+	max := parallel.CollectorFuncs[int, int, int]{
+		NewFunc: func() int { return 0 },
+		AddFunc: func(acc int, e int) int {
+			if e > acc {
+				return e
+			}
+			return acc
+		},
+		MergeFunc: func(a int, b int) int {
+			if b > a {
+				return b
+			}
+			return a
+		},
+	}
+
+	if m := parallel.Collect([]int{3, 9, 2, 7}, max); m != 9 {
+		t.Error("require 9", m)
+	}
+}
+
+func TestForEachAccum(t *testing.T) {
+	words := strings.Fields("a b a c b a")
+	counts := parallel.ForEachAccum(words, func() map[string]int {
+		return map[string]int{}
+	}, func(a map[string]int, w string) {
+		a[w]++
+	}, func(a, b map[string]int) map[string]int {
+		for k, v := range b {
+			a[k] += v
+		}
+		return a
+	})
+
+	expect := map[string]int{"a": 3, "b": 2, "c": 1}
+	if !reflect.DeepEqual(counts, expect) {
+		t.Error("require", expect, counts)
+	}
+}
+
+func TestCollectPanic(t *testing.T) {
+	defer func() {
+		p, ok := recover().(*parallel.PanicError)
+		if !ok || p.Value != "hello" {
+			t.Error("require the panic of the collector", p)
+		}
+	}()
+	parallel.GroupBy([]int{1, 2, 3, 4, 5, 6, 7, 8}, func(e int) int {
+		if e == 5 {
+			panic("hello")
+		}
+		return e % 2
+	})
+	t.Error("require a panic")
+}
@@ -0,0 +1,50 @@
+package parallel
+
+import (
+	"runtime"
+)
+
+//cooperativeMode is whether a loop yields between iterations
+type cooperativeMode int
+
+const (
+	//cooperativeAuto yields on js and wasip1 or when GOMAXPROCS is 1
+	cooperativeAuto cooperativeMode = iota
+	cooperativeOn
+	cooperativeOff
+)
+
+//WithCooperative sets whether the loop yields the processor between iterations.
+//when it yields, the dispatcher lets the started iteration run before it starts the next one,
+//so a single thread does not build up a burst of goroutines
+//and other goroutines, such as the event loop of WebAssembly, keep running.
+//by default it yields when GOOS is js or wasip1 or GOMAXPROCS is 1
+func WithCooperative(enabled bool) Option {
+	return func(c *config) {
+		if enabled {
+			c.cooperative = cooperativeOn
+		} else {
+			c.cooperative = cooperativeOff
+		}
+	}
+}
+
+//yields reports whether a loop of m yields between iterations
+func (m cooperativeMode) yields() bool {
+	switch m {
+	case cooperativeOn:
+		return true
+	case cooperativeOff:
+		return false
+	}
+	return singleThreaded()
+}
+
+//singleThreaded reports whether goroutines share a single thread
+func singleThreaded() bool {
+	switch runtime.GOOS {
+	case "js", "wasip1":
+		return true
+	}
+	return runtime.GOMAXPROCS(0) == 1
+}
@@ -0,0 +1,46 @@
+package parallel_test
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithCooperative(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	var running, peak, count int32
+	parallel.ForWithOptions(context.Background(), 0, 1000, func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&count, 1)
+		atomic.AddInt32(&running, -1)
+	}, parallel.WithCooperative(true))
+
+	if count != 1000 {
+		t.Error("require 1000", count)
+	}
+	if peak >= 100 {
+		t.Error("require no burst", peak)
+	}
+}
+
+func TestWithCooperativeBlocking(t *testing.T) {
+	//iterations that wait for each other still run concurrently
+	ch := make(chan int)
+	parallel.ForWithOptions(context.Background(), 0, 2, func(i int) {
+		if i == 0 {
+			<-ch
+		} else {
+			ch <- 1
+		}
+	}, parallel.WithCooperative(true))
+}
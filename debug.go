@@ -0,0 +1,140 @@
+package parallel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//activeIteration is an iteration of a loop that has not finished yet
+type activeIteration struct {
+	loop   *loop
+	worker int
+	index  int
+	start  time.Time
+}
+
+//activeIterations holds *activeIteration by goroutine id
+var activeIterations sync.Map
+
+//dumpActive is whether the loops register their iterations for DumpActive
+var dumpActive atomic.Bool
+
+//EnableDumpActive turns the registration of running iterations for [DumpActive] on or off.
+//it is off by default because it costs a stack trace and an allocation per iteration.
+//only the loops that start while it is on are registered
+//
+// func TestMain(m *testing.M) {
+// 		parallel.EnableDumpActive(true)
+// 		os.Exit(m.Run())
+// }
+func EnableDumpActive(enabled bool) {
+	dumpActive.Store(enabled)
+}
+
+//trackIteration registers the iteration running in the goroutine of id in active
+//and returns the function that unregisters it.
+//worker is -1 if the iteration runs in its own goroutine
+func trackIteration(active *sync.Map, id uint64, l *loop, worker int, index int) func() {
+	active.Store(id, &activeIteration{
+		loop:   l,
+		worker: worker,
+		index:  index,
+		start:  time.Now(),
+	})
+	return func() {
+		active.Delete(id)
+	}
+}
+
+//goroutineID returns the id of the current goroutine
+//if l registers its iterations or tracks leaks, or 0
+func (l *loop) goroutineID() uint64 {
+	if !l.tracking && l.leaks == nil {
+		return 0
+	}
+	return goroutineID()
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+//goroutineID parses the id of the current goroutine from its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	return parseGoroutineID(buf[:runtime.Stack(buf[:], false)])
+}
+
+//parseGoroutineID parses the id from the first line of a stack trace
+//"goroutine 123 [running]:"
+func parseGoroutineID(stack []byte) uint64 {
+	stack = bytes.TrimPrefix(stack, goroutinePrefix)
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		stack = stack[:i]
+	}
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+	return id
+}
+
+//goroutineStacks returns the stack traces of all goroutines by goroutine id
+func goroutineStacks() map[uint64][]byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[uint64][]byte)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		stacks[parseGoroutineID(stack)] = stack
+	}
+	return stacks
+}
+
+//DumpActive writes the stacks of goroutines that are still running iterations
+//of [For], [ForEach] and the functions built on them, longest running first.
+//it is useful to see which items are stuck in a hung loop.
+//it only sees the loops started after [EnableDumpActive] turned the registration on
+//
+// parallel.DumpActive(os.Stderr)
+func DumpActive(w io.Writer) error {
+	return dumpStacks(w, &activeIterations, func(it *activeIteration) string {
+		return fmt.Sprintf("iteration %d of [%d, %d)", it.index, it.loop.begin, it.loop.end)
+	})
+}
+
+//dumpStacks writes the stacks of the goroutines registered in active, longest running first.
+//name describes the iteration of a goroutine
+func dumpStacks(w io.Writer, active *sync.Map, name func(it *activeIteration) string) error {
+	type dump struct {
+		it    *activeIteration
+		stack []byte
+	}
+	var dumps []dump
+	for id, stack := range goroutineStacks() {
+		if it, ok := active.Load(id); ok {
+			dumps = append(dumps, dump{it.(*activeIteration), stack})
+		}
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].it.start.Before(dumps[j].it.start)
+	})
+
+	now := time.Now()
+	for _, d := range dumps {
+		_, err := fmt.Fprintf(w, "%s running for %v\n%s\n\n", name(d.it), now.Sub(d.it.start), d.stack)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestDumpActive(t *testing.T) {
+	parallel.EnableDumpActive(true)
+	t.Cleanup(func() {
+		parallel.EnableDumpActive(false)
+	})
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		//sequential, so iteration 0 has returned and iteration 2 has not started while 1 waits
+		parallel.ForWithOptions(context.Background(), 0, 3, func(i int) {
+			if i == 1 {
+				close(entered)
+				<-release
+			}
+		}, parallel.WithSequential())
+	}()
+
+	<-entered
+	var buf bytes.Buffer
+	if err := parallel.DumpActive(&buf); err != nil {
+		t.Error(err)
+	}
+	close(release)
+	<-done
+
+	out := buf.String()
+	if !strings.Contains(out, "iteration 1 of [0, 3)") {
+		t.Error("require hung iteration 1", out)
+	}
+	if strings.Contains(out, "iteration 0 ") || strings.Contains(out, "iteration 2 ") {
+		t.Error("finished iterations must not be dumped", out)
+	}
+}
+
+func TestDumpActiveDisabled(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		parallel.For(0, 1, func(i int) {
+			close(entered)
+			<-release
+		})
+	}()
+
+	<-entered
+	var buf bytes.Buffer
+	if err := parallel.DumpActive(&buf); err != nil {
+		t.Error(err)
+	}
+	close(release)
+	<-done
+
+	if buf.Len() != 0 {
+		t.Error("iterations must not be registered by default", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//ErrSourceClosed is returned by [AppendableSource.Append] after Close
+var ErrSourceClosed = errors.New("parallel: source closed")
+
+//AppendableSource is a queue of items that producers append over time
+//and [Drain] consumes. it is safe for concurrent use
+type AppendableSource[T any] struct {
+	mu     sync.Mutex
+	items  []T
+	closed bool
+
+	//changed is closed and replaced when items are appended or the source is closed
+	changed chan struct{}
+}
+
+//NewAppendableSource creates an empty AppendableSource
+func NewAppendableSource[T any]() *AppendableSource[T] {
+	return &AppendableSource[T]{changed: make(chan struct{})}
+}
+
+//Append adds items to the end of the source.
+//after Close, it returns ErrSourceClosed
+func (s *AppendableSource[T]) Append(items ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSourceClosed
+	}
+	s.items = append(s.items, items...)
+	s.notify()
+	return nil
+}
+
+//Close tells that no more items are appended.
+//[Drain] ends after the items already appended are consumed
+func (s *AppendableSource[T]) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		s.notify()
+	}
+}
+
+//Len returns the number of items that are not consumed yet
+func (s *AppendableSource[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+//notify wakes up the waiting consumers.
+//s.mu must be held
+func (s *AppendableSource[T]) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+//next removes the first item and returns it.
+//if there is none, it waits for an item.
+//ok is false when the source is closed and empty or ctx is done
+func (s *AppendableSource[T]) next(ctx context.Context) (item T, ok bool) {
+	for {
+		s.mu.Lock()
+		if len(s.items) > 0 {
+			item = s.items[0]
+			var zero T
+			s.items[0] = zero
+			s.items = s.items[1:]
+			s.mu.Unlock()
+			return item, true
+		}
+		closed, changed := s.closed, s.changed
+		s.mu.Unlock()
+
+		if closed {
+			return item, false
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return item, false
+		}
+	}
+}
+
+//Drain calls f for each item of src in parallel using workers goroutines
+//until src is closed and all its items are consumed or ctx is done.
+//items appended while draining are also consumed.
+//if workers <= 0, runtime.GOMAXPROCS(0) is used
+//
+// src := parallel.NewAppendableSource[string]()
+// go func() {
+// 		for line := range lines {
+// 			src.Append(line)
+// 		}
+// 		src.Close()
+// }()
+// parallel.Drain(ctx, src, 4, func(line string) {
+// 		fmt.Println(line)
+// })
+func Drain[T any](ctx context.Context, src *AppendableSource[T], workers int, f func(T)) {
+	startWorkers(workers, func() {
+		for {
+			item, ok := src.next(ctx)
+			if !ok {
+				return
+			}
+			callRecover(func() {
+				f(item)
+			})
+		}
+	}).Wait()
+}
@@ -0,0 +1,60 @@
+package parallel_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestDrain(t *testing.T) {
+	src := parallel.NewAppendableSource[int]()
+	go func() {
+		for i := 1; i <= 100; i++ {
+			src.Append(i)
+			if i%10 == 0 {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		src.Close()
+	}()
+
+	var sum int64
+	parallel.Drain(context.Background(), src, 4, func(v int) {
+		atomic.AddInt64(&sum, int64(v))
+	})
+
+	if sum != 5050 {
+		t.Error("require 5050", sum)
+	}
+	if src.Len() != 0 {
+		t.Error("require empty", src.Len())
+	}
+}
+
+func TestDrainAppendAfterClose(t *testing.T) {
+	src := parallel.NewAppendableSource[int]()
+	src.Close()
+	if err := src.Append(1); err != parallel.ErrSourceClosed {
+		t.Error("require closed", err)
+	}
+}
+
+func TestDrainContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	src := parallel.NewAppendableSource[int]()
+	src.Append(1, 2, 3)
+
+	var count int32
+	parallel.Drain(ctx, src, 2, func(v int) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	if count != 3 {
+		t.Error("require 3", count)
+	}
+}
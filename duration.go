@@ -0,0 +1,39 @@
+package parallel
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+//ForDuration calls f repeatedly in workers goroutines until d has elapsed
+//and returns the number of calls.
+//iteration is the number of the call, starting with 0.
+//a call that started before d elapsed is not interrupted, so ForDuration may return after d.
+//if workers <= 0, runtime.GOMAXPROCS(0) is used
+//
+// n := parallel.ForDuration(10*time.Second, 16, func(iteration int) {
+// 		http.Get(url)
+// })
+// fmt.Println(float64(n)/10, "requests per second")
+func ForDuration(d time.Duration, workers int, f func(iteration int)) int {
+	return ForDurationWithContext(emptyContext, d, workers, f)
+}
+
+//ForDurationWithContext is [ForDuration] that also stops when ctx is done
+func ForDurationWithContext(ctx context.Context, d time.Duration, workers int, f func(iteration int)) int {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	next := int64(0)
+	startWorkers(workers, func() {
+		for ctx.Err() == nil {
+			it := int(atomic.AddInt64(&next, 1) - 1)
+			callRecover(func() {
+				f(it)
+			})
+		}
+	}).Wait()
+
+	return int(atomic.LoadInt64(&next))
+}
@@ -0,0 +1,47 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForDuration(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	begin := time.Now()
+	n := parallel.ForDuration(100*time.Millisecond, 4, func(iteration int) {
+		mu.Lock()
+		seen[iteration] = true
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+	})
+
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Error("require 100ms", elapsed)
+	}
+	if n == 0 || n != len(seen) {
+		t.Error("bad count", n, len(seen))
+	}
+	for i := 0; i < n; i++ {
+		if !seen[i] {
+			t.Error("missing iteration", i)
+		}
+	}
+}
+
+func TestForDurationWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	parallel.ForDurationWithContext(ctx, time.Minute, 2, func(iteration int) {
+		time.Sleep(time.Millisecond)
+	})
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Error("require stop by context", elapsed)
+	}
+}
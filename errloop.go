@@ -0,0 +1,65 @@
+package parallel
+
+import (
+	"context"
+)
+
+//ErrLoop type is used in the ForErr function
+type ErrLoop func(i int) error
+
+//ForErr function repeats in parallel, starting with begin and ending with end,
+//and returns the errors of the iterations.
+//a panic is returned as a *PanicError.
+//the errors are joined, or returned as *Errors with [WithCollectAllErrors].
+//if ctx is done, it returns ctx.Err()
+//
+// err := parallel.ForErr(ctx, 0, len(urls), func(i int) error {
+// 		return fetch(urls[i])
+// }, parallel.WithCollectAllErrors(100))
+func ForErr(ctx context.Context, begin int, end int, f ErrLoop, opts ...Option) error {
+	cfg := newConfig(opts)
+	errs := newErrorCollector("iteration", cfg)
+	forErrWithConfig(ctx, begin, end, func(i int) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+			err = errs.add(i, nil, err)
+		}()
+
+		return f(i)
+	}, cfg)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+//ForEachMapOfErr loops the map in parallel and returns the errors of the iterations.
+//with [WithCollectAllErrors], each error of *Errors holds the key of the iteration
+func ForEachMapOfErr[K comparable, V any](ctx context.Context, m map[K]V, f func(k K, v V) error, opts ...Option) error {
+	cfg := newConfig(opts)
+	errs := newErrorCollector("iteration", cfg)
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	keys = sortKeys(keys, cfg)
+	forErrRange(ctx, len(keys), func(i int) (err error) {
+		k := keys[i]
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+			err = errs.add(i, k, err)
+		}()
+
+		return f(k, m[k])
+	}, cfg)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
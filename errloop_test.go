@@ -0,0 +1,134 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+var errOdd = errors.New("odd")
+
+func TestForErrJoined(t *testing.T) {
+	err := parallel.ForErr(context.Background(), 0, 10, func(i int) error {
+		if i%2 == 1 {
+			return errOdd
+		}
+		return nil
+	})
+
+	if !errors.Is(err, errOdd) {
+		t.Error("require odd", err)
+	}
+	if err := parallel.ForErr(context.Background(), 0, 10, func(i int) error { return nil }); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestForErrCollectAll(t *testing.T) {
+	err := parallel.ForErr(context.Background(), 0, 10, func(i int) error {
+		if i == 4 {
+			panic("hello")
+		}
+		if i%2 == 1 {
+			return errOdd
+		}
+		return nil
+	}, parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) {
+		t.Fatal("require Errors", err)
+	}
+	if len(errs.Items) != 6 || errs.Omitted != 0 {
+		t.Error("require 6 errors", errs)
+	}
+	for i := 1; i < len(errs.Items); i++ {
+		if errs.Items[i-1].Index > errs.Items[i].Index {
+			t.Error("require ordered by index", errs)
+		}
+	}
+
+	var p *parallel.PanicError
+	if !errors.As(err, &p) || !errors.Is(err, errOdd) {
+		t.Error("require panic and odd", err)
+	}
+}
+
+func TestForErrCollectAllMax(t *testing.T) {
+	err := parallel.ForErr(context.Background(), 0, 10, func(i int) error {
+		return errOdd
+	}, parallel.WithCollectAllErrors(3))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 3 || errs.Omitted != 7 {
+		t.Error("require 3 retained and 7 omitted", err)
+	}
+}
+
+func TestForEachMapOfErr(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	err := parallel.ForEachMapOfErr(context.Background(), m, func(k string, v int) error {
+		if v == 2 {
+			return errOdd
+		}
+		return nil
+	}, parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 1 || errs.Items[0].Key != "b" {
+		t.Error("require error of key b", err)
+	}
+}
+
+func TestAllWithCollectAllErrors(t *testing.T) {
+	err := parallel.AllWithOptions(context.Background(), []parallel.TaskFunc{
+		func() {},
+		func() { panic("fail") },
+	}, parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 1 || errs.Items[0].Index != 1 {
+		t.Error("require error of task 1", err)
+	}
+}
+
+func TestWithErrorMapper(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	err := parallel.ForErr(context.Background(), 0, len(names), func(i int) error {
+		return errOdd
+	}, parallel.WithErrorMapper(func(i int, err error) error {
+		if names[i] == "b" {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", names[i], err)
+	}), parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 2 {
+		t.Fatal("require 2 errors", err)
+	}
+	if errs.Items[0].Err.Error() != "a: odd" || !errors.Is(errs.Items[1], errOdd) {
+		t.Error("require mapped errors", errs)
+	}
+}
+
+func TestWithIgnoreErrors(t *testing.T) {
+	errNotFound := errors.New("not found")
+	p := &parallel.Progress{}
+	err := parallel.ForErr(context.Background(), 0, 4, func(i int) error {
+		if i == 0 {
+			return errOdd
+		}
+		return fmt.Errorf("item %d: %w", i, errNotFound)
+	}, parallel.WithIgnoreErrors(parallel.MatchError(errNotFound)), parallel.WithMonitor(p))
+
+	if !errors.Is(err, errOdd) || errors.Is(err, errNotFound) {
+		t.Error("require only odd", err)
+	}
+	if p.Failed() != 1 || p.Completed() != 3 {
+		t.Error("ignored errors must not be failures", p.Failed(), p.Completed())
+	}
+}
@@ -0,0 +1,227 @@
+package parallel
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//ErrTimeout is returned when a task does not finish within Policy.Timeout
+var ErrTimeout = errors.New("parallel: timeout")
+
+//ErrInvalidRange is returned by [ForStrict] when the range is reversed
+var ErrInvalidRange = errors.New("parallel: invalid range")
+
+//ErrLengthMismatch is returned by [ForEachZipOf] when the slices have different lengths
+var ErrLengthMismatch = errors.New("parallel: length mismatch")
+
+//PanicError is returned when a function panics
+type PanicError struct {
+	//Value is the value passed to panic
+	Value interface{}
+
+	//Stack is the stack trace of the goroutine that panicked
+	Stack []byte
+}
+
+//newPanicError must be called in the deferred function that recovered r
+func newPanicError(r interface{}) *PanicError {
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("parallel: panic: %v", e.Value)
+}
+
+//IterationError is an error of an iteration held by [Errors]
+type IterationError struct {
+	//Index is the index of the iteration or the task
+	Index int
+
+	//Key is the map key of the iteration.
+	//it is nil when the iteration is not over a map
+	Key interface{}
+
+	//Err is the error of the iteration
+	Err error
+}
+
+func (e *IterationError) Error() string {
+	if e.Key != nil {
+		return fmt.Sprintf("key %v: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+func (e *IterationError) Unwrap() error {
+	return e.Err
+}
+
+//Errors holds the errors of all failed iterations, ordered by index.
+//it is returned with [WithCollectAllErrors]
+type Errors struct {
+	//Items are the retained errors
+	Items []*IterationError
+
+	//Omitted is the number of errors that were not retained
+	//because there were more than the limit of [WithCollectAllErrors]
+	Omitted int
+}
+
+func (e *Errors) Error() string {
+	var b strings.Builder
+	for i, item := range e.Items {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(item.Error())
+	}
+	if e.Omitted > 0 {
+		fmt.Fprintf(&b, "\n(and %d more errors)", e.Omitted)
+	}
+	return b.String()
+}
+
+//Unwrap returns the retained errors, so errors.Is and errors.As see each of them
+func (e *Errors) Unwrap() []error {
+	errs := make([]error, len(e.Items))
+	for i, item := range e.Items {
+		errs[i] = item
+	}
+	return errs
+}
+
+//WithCollectAllErrors makes the functions that return errors
+//return *Errors that holds the error of each failed iteration with its index or key.
+//at most max errors are retained to bound memory. if max <= 0, all errors are retained
+func WithCollectAllErrors(max int) Option {
+	return func(c *config) {
+		c.collectAllErrors = true
+		c.maxErrors = max
+	}
+}
+
+//WithErrorMapper applies mapper to the error of each iteration
+//before it is collected, so errors can be wrapped with domain context in one place.
+//if mapper returns nil, the error is ignored
+//
+// parallel.ForErr(ctx, 0, len(urls), fetch, parallel.WithErrorMapper(func(i int, err error) error {
+// 		return fmt.Errorf("%s: %w", urls[i], err)
+// }))
+func WithErrorMapper(mapper func(i int, err error) error) Option {
+	return func(c *config) {
+		c.errorMapper = mapper
+	}
+}
+
+//WithIgnoreErrors treats the errors that one of matchers returns true for as success.
+//they are not collected and not counted as failures
+//
+// parallel.ForErr(ctx, 0, len(ids), remove, parallel.WithIgnoreErrors(parallel.MatchError(ErrNotFound)))
+func WithIgnoreErrors(matchers ...func(error) bool) Option {
+	return func(c *config) {
+		c.ignoreErrors = append(c.ignoreErrors, matchers...)
+	}
+}
+
+//MatchError returns a matcher of [WithIgnoreErrors] that is true for errors.Is(err, target)
+func MatchError(target error) func(error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+//ignored reports whether err is treated as success by WithIgnoreErrors
+func (c *config) ignored(err error) bool {
+	for _, match := range c.ignoreErrors {
+		if match(err) {
+			return true
+		}
+	}
+	return false
+}
+
+//errorCollector collects the errors of iterations
+type errorCollector struct {
+	mu         sync.Mutex
+	name       string
+	collectAll bool
+	max        int
+	mapper     func(i int, err error) error
+	cfg        *config
+	items      []*IterationError
+	omitted    int
+}
+
+//newErrorCollector creates an errorCollector.
+//name is the word before the index in the joined error
+func newErrorCollector(name string, cfg *config) *errorCollector {
+	return &errorCollector{
+		name:       name,
+		collectAll: cfg.collectAllErrors,
+		max:        cfg.maxErrors,
+		mapper:     cfg.errorMapper,
+		cfg:        cfg,
+	}
+}
+
+//add adds the error of the iteration index and returns the error that is added.
+//a nil err and errors of WithIgnoreErrors are ignored
+func (c *errorCollector) add(index int, key interface{}, err error) error {
+	if err != nil && c.cfg.ignored(err) {
+		return nil
+	}
+	if err != nil && c.mapper != nil {
+		err = c.mapper(index, err)
+	}
+	if err == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.collectAll && c.max > 0 && len(c.items) >= c.max {
+		c.omitted++
+		return err
+	}
+	c.items = append(c.items, &IterationError{Index: index, Key: key, Err: err})
+	return err
+}
+
+//err returns the collected errors and the error of the BudgetStore of the loop.
+//with WithCollectAllErrors, it is *Errors, otherwise the errors are joined
+func (c *errorCollector) err() error {
+	if c.cfg.budget != nil {
+		if i, err := c.cfg.budget.takeStoreErr(); err != nil {
+			c.add(i, nil, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.items) == 0 && c.omitted == 0 {
+		return nil
+	}
+
+	sort.Slice(c.items, func(i, j int) bool {
+		return c.items[i].Index < c.items[j].Index
+	})
+	if c.collectAll {
+		return &Errors{Items: c.items, Omitted: c.omitted}
+	}
+
+	errs := make([]error, len(c.items))
+	for i, item := range c.items {
+		if item.Key != nil {
+			errs[i] = fmt.Errorf("key %v: %w", item.Key, item.Err)
+		} else {
+			errs[i] = fmt.Errorf("%s %d: %w", c.name, item.Index, item.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
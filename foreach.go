@@ -0,0 +1,424 @@
+//go:build !parallel_noreflect
+
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var emptyIn = []reflect.Value{}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+//reflectFunc is a function that is called by reflection for each iteration of a loop from 0 to end.
+//if the first parameter of the function is a context.Context,
+//the context of the iteration is passed to it and NumIn and In skip the parameter
+type reflectFunc struct {
+	fn          reflect.Value
+	typ         reflect.Type
+	withContext bool
+	ctx         context.Context
+	end         int
+	cfg         *config
+}
+
+func newReflectFunc(ctx context.Context, f interface{}, end int, cfg *config) reflectFunc {
+	typ := reflect.TypeOf(f)
+	return reflectFunc{
+		fn:          reflect.ValueOf(f),
+		typ:         typ,
+		withContext: typ.NumIn() > 0 && typ.In(0) == contextType,
+		ctx:         ctx,
+		end:         end,
+		cfg:         cfg,
+	}
+}
+
+//NumIn returns the number of the parameters after the context
+func (f reflectFunc) NumIn() int {
+	if f.withContext {
+		return f.typ.NumIn() - 1
+	}
+	return f.typ.NumIn()
+}
+
+//In returns the type of the i'th parameter after the context
+func (f reflectFunc) In(i int) reflect.Type {
+	if f.withContext {
+		return f.typ.In(i + 1)
+	}
+	return f.typ.In(i)
+}
+
+//Call calls the function for the iteration it with args
+func (f reflectFunc) Call(it int, args []reflect.Value) {
+	if f.withContext {
+		ctx, done := iterationContext(f.ctx, Meta{Index: it, End: f.end, Worker: -1}, f.cfg)
+		defer done()
+		args = append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, args...)
+	}
+	f.fn.Call(args)
+}
+
+//ForEachSlice loops the slice in parallel
+//If put multiple options, only the first one is valid.
+//slice: slice, array
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+//
+// s := []int{1,2,3,4,5}
+// parallel.ForEachSlice(s, func(i int, e int) {
+// 		fmt.Println(i, e)
+// })
+func ForEachSlice(slice interface{}, f interface{}) {
+	ForEachSliceWithContext(emptyContext, slice, f)
+}
+
+//ForEachSliceWithContext loops the slice in parallel
+//If put multiple options, only the first one is valid.
+//slice: slice, array
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+//
+// s := []int{1,2,3,4,5}
+// parallel.ForEachSlice(s, func(i int, e int) {
+// 		fmt.Println(i, e)
+// })
+func ForEachSliceWithContext(ctx context.Context, slice interface{}, f interface{}) {
+	forEachSliceWithConfig(ctx, slice, f, &config{})
+}
+
+//ForEachSliceWithOptions is [ForEachSliceWithContext] configured by opts
+func ForEachSliceWithOptions(ctx context.Context, slice interface{}, f interface{}, opts ...Option) {
+	forEachSliceWithConfig(ctx, slice, f, newConfig(opts))
+}
+
+func forEachSliceWithConfig(ctx context.Context, slice interface{}, f interface{}, cfg *config) {
+	reflectionSlice := reflect.ValueOf(slice)
+
+	if reflectionSlice.Len() == 0 {
+		return
+	}
+
+	ctx = withRunID(ctx)
+	reflectionFunc := newReflectFunc(ctx, f, reflectionSlice.Len(), cfg)
+	funcType := reflectionFunc
+	funcArgc := funcType.NumIn()
+
+	sliceType := reflect.TypeOf(slice)
+
+	if funcArgc == 2 {
+		/**
+		* for i, e := range slice {
+		*	f(i, e)
+		* }
+		**/
+
+		if !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
+			//reflect.TypeOf(0) = int type
+			panic("first argument is not an int")
+		}
+
+		if elemType, argType := sliceType.Elem(), funcType.In(1); !elemType.AssignableTo(argType) {
+			panic(fmt.Sprintf("slice value type: %v but func second arg type: %v", elemType, argType))
+		}
+
+		forRange(ctx, reflectionSlice.Len(), func(i int) {
+			reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(i), reflectionSlice.Index(i)})
+		}, cfg)
+	} else if funcArgc == 1 {
+		/**
+		* for i := range slice {
+		*	f(i)
+		* }
+		**/
+
+		if !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
+			//reflect.TypeOf(0) = int type
+			panic("first argument is not an int")
+		}
+
+		forRange(ctx, reflectionSlice.Len(), func(i int) {
+			reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(i)})
+		}, cfg)
+	} else if funcArgc == 0 {
+		/**
+		* for _ := range slice {
+		*	f()
+		* }
+		**/
+		forRange(ctx, reflectionSlice.Len(), func(i int) {
+			reflectionFunc.Call(i, emptyIn)
+		}, cfg)
+	}
+}
+
+//ForEachMap loops the Map in parallel
+//If put multiple options, only the first one is valid.
+//m: map
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+// a := map[string]int{
+// 	"a": 1,
+// 	"b": 2,
+// 	"c": 3,
+// 	"d": 4,
+// 	"e": 5,
+// }
+// parallel.ForEachMap(a, func(k string, v int) {
+// 		fmt.Println(k, v)
+// })
+func ForEachMap(m interface{}, f interface{}) {
+	ForEachMapWithContext(emptyContext, m, f)
+}
+
+//ForEachMapWithContext loops the Map in parallel
+//If put multiple options, only the first one is valid.
+//m: map
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+// a := map[string]int{
+// 	"a": 1,
+// 	"b": 2,
+// 	"c": 3,
+// 	"d": 4,
+// 	"e": 5,
+// }
+// parallel.ForEachMap(a, func(k string, v int) {
+// 		fmt.Println(k, v)
+// })
+func ForEachMapWithContext(ctx context.Context, m interface{}, f interface{}) {
+	forEachMapWithConfig(ctx, m, f, &config{})
+}
+
+//ForEachMapWithOptions is [ForEachMapWithContext] configured by opts
+func ForEachMapWithOptions(ctx context.Context, m interface{}, f interface{}, opts ...Option) {
+	forEachMapWithConfig(ctx, m, f, newConfig(opts))
+}
+
+func forEachMapWithConfig(ctx context.Context, m interface{}, f interface{}, cfg *config) {
+	reflectionMap := reflect.ValueOf(m)
+
+	if reflectionMap.Len() == 0 {
+		return
+	}
+
+	mapType := reflectionMap.Type()
+	mapKeys := cfg.mapKeys(reflectionMap)
+
+	ctx = withRunID(ctx)
+	reflectionFunc := newReflectFunc(ctx, f, len(mapKeys), cfg)
+	funcType := reflectionFunc
+	funcArgc := funcType.NumIn()
+
+	if funcArgc == 2 {
+		/**
+		* for k, v := range m {
+		*	f(k, v)
+		* }
+		**/
+		if keyType, argType := mapType.Key(), funcType.In(0); !keyType.AssignableTo(argType) {
+			panic(fmt.Sprintf("map keyType: %v but func first argType: %v", keyType, argType))
+		}
+		if valType, argType := mapType.Elem(), funcType.In(1); !valType.AssignableTo(argType) {
+			panic(fmt.Sprintf("map valueType: %v but func second argType: %v", valType, argType))
+		}
+		forRange(ctx, len(mapKeys), func(i int) {
+			key := mapKeys[i]
+			reflectionFunc.Call(i, []reflect.Value{key, reflectionMap.MapIndex(key)})
+		}, cfg)
+	} else if funcArgc == 1 {
+		/**
+		* for k := range m {
+		*	f(k)
+		* }
+		**/
+		if keyType, argType := mapType.Key(), funcType.In(0); !keyType.AssignableTo(argType) {
+			panic(fmt.Sprintf("map key: %v but function first arg: %v", keyType, argType))
+		}
+		forRange(ctx, len(mapKeys), func(i int) {
+			reflectionFunc.Call(i, []reflect.Value{mapKeys[i]})
+		}, cfg)
+	} else if funcArgc == 0 {
+		/**
+		* for _ := range m {
+		*	f()
+		* }
+		**/
+		forRange(ctx, len(mapKeys), func(i int) {
+			reflectionFunc.Call(i, emptyIn)
+		}, cfg)
+	}
+
+}
+
+//ForEach loops the collection in parallel
+//collection: slice, array, map, string, struct, pointer to struct
+//If put multiple options, only the first one is valid.
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+//
+// ex1)
+// s := []int{1,2,3,4,5}
+// parallel.ForEach(s, func(i int, e int) {
+// 		fmt.Println(i, e)
+// })
+//
+// ex2)
+// a := map[string]int{
+// 	"a": 1,
+// 	"b": 2,
+// 	"c": 3,
+// 	"d": 4,
+// 	"e": 5,
+// }
+// parallel.ForEach(a, func(k string, v int) {
+// 		fmt.Println(k, v)
+// })
+func ForEach(collection interface{}, f interface{}) {
+	ForEachWithContext(emptyContext, collection, f)
+}
+
+//ForEachWithContext loops the collection in parallel
+//collection: slice, array, map, string, struct, pointer to struct
+//If put multiple options, only the first one is valid.
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+//
+// ex1)
+// s := []int{1,2,3,4,5}
+// parallel.ForEach(s, func(i int, e int) {
+// 		fmt.Println(i, e)
+// })
+//
+// ex2)
+// a := map[string]int{
+// 	"a": 1,
+// 	"b": 2,
+// 	"c": 3,
+// 	"d": 4,
+// 	"e": 5,
+// }
+// parallel.ForEach(a, func(k string, v int) {
+// 		fmt.Println(k, v)
+// })
+func ForEachWithContext(ctx context.Context, collection interface{}, f interface{}) {
+	ForEachWithOptions(ctx, collection, f)
+}
+
+//ForEachWithOptions is [ForEachWithContext] configured by opts
+func ForEachWithOptions(ctx context.Context, collection interface{}, f interface{}, opts ...Option) {
+	collectionKind := reflect.TypeOf(collection).Kind()
+
+	switch collectionKind {
+	case reflect.Slice, reflect.Array:
+		forEachSliceWithConfig(ctx, collection, f, newConfig(opts))
+	case reflect.Map:
+		forEachMapWithConfig(ctx, collection, f, newConfig(opts))
+	case reflect.String:
+		forEachStringReflect(ctx, reflect.ValueOf(collection).String(), f, opts)
+	case reflect.Struct:
+		forEachStructWithConfig(ctx, collection, f, newConfig(opts))
+	case reflect.Ptr:
+		if reflect.TypeOf(collection).Elem().Kind() == reflect.Struct {
+			forEachStructWithConfig(ctx, collection, f, newConfig(opts))
+		}
+	}
+}
+
+//forEachStringReflect is used by [ForEachWithContext] for strings
+//if the second argument of f is a byte, it loops the bytes
+//otherwise it loops the runes
+func forEachStringReflect(ctx context.Context, s string, f interface{}, opts []Option) {
+	ctx = withRunID(ctx)
+	reflectionFunc := newReflectFunc(ctx, f, len(s), newConfig(opts))
+	funcType := reflectionFunc
+	funcArgc := funcType.NumIn()
+
+	if funcArgc >= 1 && !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
+		//reflect.TypeOf(0) = int type
+		panic("first argument is not an int")
+	}
+
+	elemType := reflect.TypeOf(rune(0))
+	if funcArgc == 2 {
+		if funcType.In(1) == reflect.TypeOf(byte(0)) {
+			opts = append(opts[:len(opts):len(opts)], WithBytes())
+			elemType = funcType.In(1)
+		} else if argType := funcType.In(1); !elemType.AssignableTo(argType) {
+			panic(fmt.Sprintf("string value type: %v but func second arg type: %v", elemType, argType))
+		}
+	}
+
+	ForEachStringWithContext(ctx, s, func(i int, r rune) {
+		switch funcArgc {
+		case 2:
+			reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(i), reflect.ValueOf(r).Convert(elemType)})
+		case 1:
+			reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(i)})
+		case 0:
+			reflectionFunc.Call(i, emptyIn)
+		}
+	}, opts...)
+}
+
+//ForEachZip loops two slices of the same length together in parallel
+//a, b: slice, array
+//f: func(i int, ea A, eb B)
+//if the lengths are different, it panics
+//
+// in := []string{"a", "b"}
+// out := make([]int, len(in))
+// parallel.ForEachZip(in, out, func(i int, s string, _ int) {
+// 		out[i] = len(s)
+// })
+func ForEachZip(a interface{}, b interface{}, f interface{}) {
+	ForEachZipWithContext(emptyContext, a, b, f)
+}
+
+//ForEachZipWithContext loops two slices of the same length together in parallel
+//a, b: slice, array
+//f: func(i int, ea A, eb B)
+//if the lengths are different, it panics
+func ForEachZipWithContext(ctx context.Context, a interface{}, b interface{}, f interface{}) {
+	ForEachZipWithOptions(ctx, a, b, f)
+}
+
+//ForEachZipWithOptions is [ForEachZipWithContext] configured by opts
+func ForEachZipWithOptions(ctx context.Context, a interface{}, b interface{}, f interface{}, opts ...Option) {
+	cfg := newConfig(opts)
+	reflectionA := reflect.ValueOf(a)
+	reflectionB := reflect.ValueOf(b)
+	if reflectionA.Len() != reflectionB.Len() {
+		panic(fmt.Sprintf("slice length: %v but %v", reflectionA.Len(), reflectionB.Len()))
+	}
+
+	ctx = withRunID(ctx)
+	reflectionFunc := newReflectFunc(ctx, f, reflectionA.Len(), cfg)
+	funcType := reflectionFunc
+	if funcType.NumIn() != 3 {
+		panic("function must have 3 arguments")
+	}
+	if !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
+		//reflect.TypeOf(0) = int type
+		panic("first argument is not an int")
+	}
+	if elemType, argType := reflectionA.Type().Elem(), funcType.In(1); !elemType.AssignableTo(argType) {
+		panic(fmt.Sprintf("first slice value type: %v but func second arg type: %v", elemType, argType))
+	}
+	if elemType, argType := reflectionB.Type().Elem(), funcType.In(2); !elemType.AssignableTo(argType) {
+		panic(fmt.Sprintf("second slice value type: %v but func third arg type: %v", elemType, argType))
+	}
+
+	forRange(ctx, reflectionA.Len(), func(i int) {
+		reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(i), reflectionA.Index(i), reflectionB.Index(i)})
+	}, cfg)
+}
+
+//ForEachInterruptible is [ForEach] that stops on SIGINT or SIGTERM.
+//the signal handler is installed only for the duration of the call.
+//when a signal is received, no more iterations are started;
+//the iterations that are already running are not stopped and it returns ErrInterrupted after they finish
+func ForEachInterruptible(collection interface{}, f interface{}) error {
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+
+	ForEachWithOptions(context.Background(), collection, f, WithLimiter(interruptLimiter{ctx}))
+	return interruptErr(context.Background(), ctx)
+}
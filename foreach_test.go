@@ -0,0 +1,423 @@
+//go:build !parallel_noreflect
+
+package parallel_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForLoopArg(t *testing.T) {
+	s := make([]int, 1024)
+	for i := 0; i < 1024; i++ {
+		s[i] = i
+	}
+	parallel.ForEach(s, func(i int, e int) {
+		fmt.Println(i, e)
+	})
+}
+
+func TestForEachSliceDefault(t *testing.T) {
+	s := []int{5, 4, 3, 2, 1}
+	parallel.ForEach(s, func(i int, e int) {
+		fmt.Println(i, e)
+	})
+
+}
+
+func TestForEachSliceDefault2(t *testing.T) {
+	s := []int{5, 4, 3, 2, 1}
+	parallel.ForEachSlice(s, func(i int, e int) {
+		fmt.Println(i, e)
+	})
+
+}
+
+func TestForEachSliceInterface(t *testing.T) {
+	s := []int{5, 4, 3, 2, 1}
+	parallel.ForEach(s, func(i interface{}, val interface{}) {
+		fmt.Println(i, val)
+	})
+}
+
+func TestForEachSliceError(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("int slice - foreach string")
+		}
+	}()
+	s := []int{5, 4, 3, 2, 1}
+	// t.
+	parallel.ForEach(s, func(i int, e string) {
+		fmt.Println(i, e)
+	})
+}
+
+func TestForEachSliceError2(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("int slice - foreach string")
+		}
+	}()
+	s := []int{5, 4, 3, 2, 1}
+	// t.
+	parallel.ForEach(s, func(i string, e string) {
+		fmt.Println(i, e)
+	})
+}
+
+func TestForEachSliceError3(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("int slice - foreach string")
+		}
+	}()
+	s := []int{5, 4, 3, 2, 1}
+	// t.
+	parallel.ForEach(s, func(i string) {
+		fmt.Println(i)
+	})
+}
+
+func TestForEachSliceEmpty(t *testing.T) {
+
+	s := []int{}
+
+	parallel.ForEach(s, func(i int) {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachSliceSingle(t *testing.T) {
+
+	s := []int{5, 4, 3, 2, 1}
+
+	parallel.ForEach(s, func(i int) {
+		fmt.Println(i)
+	})
+}
+
+func TestForEachSliceNoArg(t *testing.T) {
+
+	s := []int{5, 4, 3, 2, 1}
+
+	parallel.ForEach(s, func() {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachMapDefault(t *testing.T) {
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEach(a, func(k string, v int) {
+		fmt.Println(k, v)
+	})
+}
+
+func TestForEachMapDefault2(t *testing.T) {
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k string, v int) {
+		fmt.Println(k, v)
+	})
+}
+
+func TestForEachMapInterface(t *testing.T) {
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k string, v interface{}) {
+		fmt.Println(k, v)
+	})
+}
+
+func TestForEachMapSingle(t *testing.T) {
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k string) {
+		fmt.Println(k)
+	})
+}
+
+func TestForEachMapNoArg(t *testing.T) {
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func() {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachMapKeyError(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("string key func int")
+		}
+	}()
+
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k int) {
+		fmt.Println("?", k)
+	})
+}
+
+func TestForEachMapValueError(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("int val func string")
+		}
+	}()
+
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k string, v string) {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachArray(t *testing.T) {
+
+	var a [2048]int
+	parallel.ForEach(a, func(i int) {
+		a[i] = rand.Int()
+	})
+
+	parallel.ForEach(a, func(_ int, e int) {
+		fmt.Println(e)
+	})
+}
+
+func TestForEachMapEmpty(t *testing.T) {
+	a := map[string]int{}
+	parallel.ForEachMap(a, func() {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachMapBadKey(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("string val func int")
+		}
+	}()
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(a int) {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachMapBadKey2(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Error("string val func int")
+		}
+	}()
+	a := map[string]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 4,
+		"e": 5,
+	}
+	parallel.ForEachMap(a, func(k int, v interface{}) {
+		fmt.Println("?")
+	})
+}
+
+func TestForEachMapAny(t *testing.T) {
+	type foo struct {
+		a int
+		b int
+	}
+	a := map[string]interface{}{
+		"a": 1,
+		"b": "b",
+		"c": foo{1, 2},
+		"d": &foo{3, 4},
+	}
+	parallel.ForEach(a, func(k string, v interface{}) {
+		fmt.Println(k, v)
+	})
+
+}
+
+func TestWithMonitorForEach(t *testing.T) {
+	p := &parallel.Progress{}
+	parallel.ForEachWithOptions(context.Background(), []int{1, 2, 3}, func(i int, e int) {
+	}, parallel.WithMonitor(p))
+
+	if p.Completed() != 3 {
+		t.Error("require 3", p.Completed())
+	}
+}
+
+func TestForEachDispatchString(t *testing.T) {
+	s := "hello"
+	m := sync.Map{}
+	parallel.ForEach(s, func(i int, b byte) {
+		m.Store(i, b)
+	})
+	for i := 0; i < len(s); i++ {
+		if v, _ := m.Load(i); v != s[i] {
+			t.Error("require", s[i], v)
+		}
+	}
+
+	parallel.ForEach(s, func(i int, r rune) {
+		if r != rune(s[i]) {
+			t.Error("require", s[i], r)
+		}
+	})
+}
+
+func TestForEachStringError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("string - foreach string")
+		}
+	}()
+	parallel.ForEach("hello", func(i int, e string) {})
+}
+
+func BenchmarkForEachReflect(b *testing.B) {
+	s := make([]int, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parallel.ForEachWithOptions(context.Background(), s, func(i int, e int) {}, parallel.WithClaiming())
+	}
+}
+
+func TestForEachZip(t *testing.T) {
+	in := []string{"a", "bb", "ccc"}
+	out := make([]int, len(in))
+	parallel.ForEachZip(in, out, func(i int, s string, _ int) {
+		out[i] = len(s)
+	})
+
+	for i, s := range in {
+		if out[i] != len(s) {
+			t.Error("require", len(s), out[i])
+		}
+	}
+}
+
+func TestForEachZipLengthError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("require length mismatch panic")
+		}
+	}()
+	parallel.ForEachZip([]int{1, 2}, []int{1}, func(i int, a int, b int) {})
+}
+
+func TestForEachZipTypeError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("require type panic")
+		}
+	}()
+	parallel.ForEachZip([]int{1}, []int{1}, func(i int, a int, b string) {})
+}
+
+func TestForEachInterruptible(t *testing.T) {
+	err := parallel.ForEachInterruptible([]int{1, 2, 3}, func(i int, e int) {})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestForEachContextArg(t *testing.T) {
+	s := []int{1, 2, 3}
+	parallel.ForEach(s, func(ctx context.Context, i int, e int) {
+		m, ok := parallel.MetaFrom(ctx)
+		if !ok || m.Index != i || m.End != len(s) {
+			t.Error("bad meta", i, m)
+		}
+		if e != s[i] {
+			t.Error("bad element", i, e)
+		}
+	})
+}
+
+type testContextKey struct{}
+
+func TestForEachMapContextArg(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey{}, "v")
+
+	var mu sync.Mutex
+	keys := map[string]bool{}
+	parallel.ForEachMapWithContext(ctx, map[string]int{"a": 1, "b": 2}, func(ctx context.Context, k string, v int) {
+		if ctx.Value(testContextKey{}) != "v" {
+			t.Error("require the context of the call")
+		}
+		mu.Lock()
+		keys[k] = true
+		mu.Unlock()
+	})
+
+	if len(keys) != 2 {
+		t.Error("require 2 keys", keys)
+	}
+}
+
+func TestForEachContextArgTrace(t *testing.T) {
+	parallel.ForEachWithOptions(context.Background(), "ab", func(ctx context.Context, i int, r rune) {
+		if parallel.TraceID(ctx) != string(r) {
+			t.Error("bad trace id", i, parallel.TraceID(ctx))
+		}
+	}, parallel.WithTraceIDs(func(i int) string {
+		return string("ab"[i])
+	}))
+}
@@ -0,0 +1,101 @@
+package parallel
+
+import (
+	"context"
+)
+
+//FutureFunc functions that are executed by [Go]
+type FutureFunc func() (interface{}, error)
+
+//Future holds the result of a function running in another goroutine
+type Future struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+//Go calls f in a new goroutine and returns a Future of its result.
+//if f panics, a *PanicError is returned as the error of the Future
+//
+// f := parallel.Go(func() (interface{}, error) {
+// 		return 1, nil
+// })
+// v, err := f.Get()
+func Go(f FutureFunc) *Future {
+	future := &Future{done: make(chan struct{})}
+	go func() {
+		defer close(future.done)
+		defer func() {
+			if r := recover(); r != nil {
+				future.err = newPanicError(r)
+			}
+		}()
+
+		future.value, future.err = f()
+	}()
+	return future
+}
+
+//Done returns a channel that is closed when the function is finished
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+//Get waits for the function to finish and returns its result
+func (f *Future) Get() (interface{}, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+//GetWithContext waits for the function to finish and returns its result.
+//if ctx is done first, it returns ctx.Err()
+//the function does not force shutdown.
+func (f *Future) GetWithContext(ctx context.Context) (interface{}, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+//AwaitAll waits for all futures to finish.
+//values are returned in the order of futures
+//and err is the error of the first future that failed
+func AwaitAll(futures ...*Future) ([]interface{}, error) {
+	values := make([]interface{}, len(futures))
+	var firstErr error
+	for i, f := range futures {
+		v, err := f.Get()
+		values[i] = v
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return values, firstErr
+}
+
+//AwaitAny waits for one of futures to finish and returns it.
+//if there are no futures, it returns nil
+func AwaitAny(futures ...*Future) *Future {
+	if len(futures) == 0 {
+		return nil
+	}
+
+	//buffered so that the goroutines of the futures that finish later do not block.
+	//each goroutine returns when its future is finished or when a winner is picked,
+	//so a future that never finishes does not keep its goroutine
+	first := make(chan *Future, len(futures))
+	picked := make(chan struct{})
+	defer close(picked)
+	for _, f := range futures {
+		go func(f *Future) {
+			select {
+			case <-f.done:
+				first <- f
+			case <-picked:
+			}
+		}(f)
+	}
+	return <-first
+}
@@ -0,0 +1,122 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestFutureGet(t *testing.T) {
+	f := parallel.Go(func() (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return 1, nil
+	})
+
+	v, err := f.Get()
+	if err != nil || v != 1 {
+		t.Error("require 1", v, err)
+	}
+
+	select {
+	case <-f.Done():
+	default:
+		t.Error("require done")
+	}
+}
+
+func TestFuturePanic(t *testing.T) {
+	f := parallel.Go(func() (interface{}, error) {
+		panic("hello")
+	})
+
+	if _, err := f.Get(); err == nil {
+		t.Error("require panic error")
+	}
+}
+
+func TestFutureGetWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	f := parallel.Go(func() (interface{}, error) {
+		time.Sleep(1 * time.Second)
+		return 1, nil
+	})
+
+	if _, err := f.GetWithContext(ctx); err != context.DeadlineExceeded {
+		t.Error("require timeout error", err)
+	}
+}
+
+func TestAwaitAll(t *testing.T) {
+	errFail := errors.New("fail")
+	values, err := parallel.AwaitAll(
+		parallel.Go(func() (interface{}, error) {
+			time.Sleep(50 * time.Millisecond)
+			return 1, nil
+		}),
+		parallel.Go(func() (interface{}, error) {
+			return 2, nil
+		}),
+		parallel.Go(func() (interface{}, error) {
+			return nil, errFail
+		}),
+	)
+
+	if err != errFail {
+		t.Error("require fail", err)
+	}
+	if values[0] != 1 || values[1] != 2 || values[2] != nil {
+		t.Error("require [1 2 nil]", values)
+	}
+}
+
+func TestAwaitAny(t *testing.T) {
+	slow := parallel.Go(func() (interface{}, error) {
+		time.Sleep(1 * time.Second)
+		return 1, nil
+	})
+	fast := parallel.Go(func() (interface{}, error) {
+		return 2, nil
+	})
+
+	if f := parallel.AwaitAny(slow, fast); f != fast {
+		t.Error("require fast future")
+	}
+
+	if parallel.AwaitAny() != nil {
+		t.Error("require nil")
+	}
+}
+
+func TestAwaitAnyNoLeak(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	never := parallel.Go(func() (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+	fast := parallel.Go(func() (interface{}, error) {
+		return 2, nil
+	})
+	<-fast.Done()
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if f := parallel.AwaitAny(never, fast); f != fast {
+			t.Fatal("require fast future")
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Error("require the waiters of the future that never finishes to return", before, n)
+	}
+}
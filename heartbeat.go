@@ -0,0 +1,154 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//heartbeatKey is the context key of *heartbeat
+type heartbeatKey struct{}
+
+//heartbeat is the last time an iteration called [Heartbeat]
+type heartbeat struct {
+	runID    string
+	index    int
+	last     int64
+	reported bool
+	cancel   context.CancelFunc
+}
+
+//Heartbeat tells that the iteration of ctx is still making progress.
+//callbacks of [ForContext] call it periodically when [WithStallDetection] is used.
+//if ctx was not passed by [ForContext], it does nothing
+func Heartbeat(ctx context.Context) {
+	if h, ok := ctx.Value(heartbeatKey{}).(*heartbeat); ok {
+		atomic.StoreInt64(&h.last, time.Now().UnixNano())
+	}
+}
+
+//Stall is an iteration that did not call [Heartbeat] for a while
+type Stall struct {
+	//RunID is the [RunID] of the loop
+	RunID string
+
+	//Index is the index of the iteration
+	Index int
+
+	//Since is the time since the last heartbeat of the iteration
+	Since time.Duration
+}
+
+type stallConfig struct {
+	timeout time.Duration
+	onStall func(Stall)
+	cancel  bool
+}
+
+//WithStallDetection calls onStall when an iteration of [ForContext]
+//does not call [Heartbeat] for d. the start of an iteration counts as a heartbeat.
+//onStall is called once for each stall, in its own goroutine,
+//so a slow onStall does not delay the other reports or [WithStallCancel].
+//it may still run after the loop returns
+//
+// parallel.ForContext(ctx, 0, len(s), func(ctx context.Context, i int) {
+// 		for _, chunk := range s[i] {
+// 			parallel.Heartbeat(ctx)
+// 			process(chunk)
+// 		}
+// }, parallel.WithStallDetection(time.Minute, func(s parallel.Stall) {
+// 		log.Println("stalled", s.Index, s.Since)
+// }))
+func WithStallDetection(d time.Duration, onStall func(s Stall)) Option {
+	return func(c *config) {
+		if c.stall == nil {
+			c.stall = &stallConfig{}
+		}
+		c.stall.timeout = d
+		c.stall.onStall = onStall
+	}
+}
+
+//WithStallCancel cancels the context of an iteration
+//when [WithStallDetection] reports it
+func WithStallCancel() Option {
+	return func(c *config) {
+		if c.stall == nil {
+			c.stall = &stallConfig{}
+		}
+		c.stall.cancel = true
+	}
+}
+
+//stallDetector checks the heartbeats of running iterations
+type stallDetector struct {
+	cfg        *stallConfig
+	heartbeats sync.Map
+	stop       chan struct{}
+}
+
+func startStallDetector(cfg *stallConfig) *stallDetector {
+	s := &stallDetector{cfg: cfg, stop: make(chan struct{})}
+	go s.run()
+	return s
+}
+
+func (s *stallDetector) run() {
+	interval := s.cfg.timeout / 2
+	if interval <= 0 {
+		//a timeout of 1ns
+		interval = 1
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.check(time.Now())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+//check reports the iterations that did not beat for the timeout
+func (s *stallDetector) check(now time.Time) {
+	s.heartbeats.Range(func(k, _ interface{}) bool {
+		h := k.(*heartbeat)
+		since := now.Sub(time.Unix(0, atomic.LoadInt64(&h.last)))
+		if since < s.cfg.timeout {
+			h.reported = false
+			return true
+		}
+		if h.reported {
+			return true
+		}
+
+		h.reported = true
+		if s.cfg.onStall != nil {
+			go s.cfg.onStall(Stall{RunID: h.runID, Index: h.index, Since: since})
+		}
+		if s.cfg.cancel {
+			h.cancel()
+		}
+		return true
+	})
+}
+
+//track registers the iteration of ctx and returns
+//the context that carries its heartbeat and the function that unregisters it
+func (s *stallDetector) track(ctx context.Context, index int) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &heartbeat{runID: RunID(ctx), index: index, last: time.Now().UnixNano(), cancel: cancel}
+	s.heartbeats.Store(h, nil)
+	return context.WithValue(ctx, heartbeatKey{}, h), func() {
+		s.heartbeats.Delete(h)
+		cancel()
+	}
+}
+
+func (s *stallDetector) close() {
+	close(s.stop)
+}
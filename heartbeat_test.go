@@ -0,0 +1,86 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestStallDetection(t *testing.T) {
+	var mu sync.Mutex
+	var stalls []parallel.Stall
+	parallel.ForContext(context.Background(), 0, 2, func(ctx context.Context, i int) {
+		for j := 0; j < 10; j++ {
+			if i == 0 {
+				parallel.Heartbeat(ctx)
+			}
+			time.Sleep(30 * time.Millisecond)
+		}
+	}, parallel.WithStallDetection(100*time.Millisecond, func(s parallel.Stall) {
+		mu.Lock()
+		stalls = append(stalls, s)
+		mu.Unlock()
+	}))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stalls) != 1 || stalls[0].Index != 1 {
+		t.Error("require one stall of iteration 1", stalls)
+	}
+}
+
+func TestStallCancel(t *testing.T) {
+	begin := time.Now()
+	parallel.ForContext(context.Background(), 0, 1, func(ctx context.Context, i int) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+			t.Error("stalled iteration must be canceled")
+		}
+	}, parallel.WithStallDetection(100*time.Millisecond, nil), parallel.WithStallCancel())
+
+	if time.Since(begin) > time.Second {
+		t.Error("require cancel")
+	}
+}
+
+func TestStallDetectionBlockingReport(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	reports := make(chan int, 2)
+	begin := time.Now()
+	parallel.ForContext(context.Background(), 0, 2, func(ctx context.Context, i int) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+			t.Error("stalled iteration must be canceled", i)
+		}
+	}, parallel.WithStallDetection(100*time.Millisecond, func(s parallel.Stall) {
+		reports <- s.Index
+		<-release
+	}), parallel.WithStallCancel())
+
+	if time.Since(begin) > time.Second {
+		t.Error("a blocked report must not delay the cancel")
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-reports:
+		case <-time.After(time.Second):
+			t.Fatal("a blocked report must not delay the other reports")
+		}
+	}
+}
+
+func TestStallDetectionTinyTimeout(t *testing.T) {
+	parallel.ForContext(context.Background(), 0, 1, func(ctx context.Context, i int) {
+		<-ctx.Done()
+	}, parallel.WithStallDetection(time.Nanosecond, nil), parallel.WithStallCancel())
+}
+
+func TestHeartbeatWithoutDetection(t *testing.T) {
+	parallel.Heartbeat(context.Background())
+}
@@ -0,0 +1,36 @@
+package parallel
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+//Middleware returns a net/http middleware that runs each request in a [Scope].
+//the Scope is read with [ScopeFrom] from the context of the request.
+//when the handler returns, the Scope is closed: the context of the Scope is canceled
+//and the middleware waits for the goroutines of the Scope,
+//that is the goroutines of [Scope.Go], [AllAsync] and [RaceAsync] started with the context of the request.
+//the iterations of a loop canceled with the request are not waited for, see [Scope].
+//the panics of the goroutines are written to os.Stderr
+//
+// mux.Handle("/report", parallel.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+// 		parallel.ScopeFrom(r.Context()).Go(func(ctx context.Context) {
+// 			audit(ctx, r.URL.Path)
+// 		})
+// 		...
+// })))
+func Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := NewScope(r.Context())
+			defer func() {
+				if err := s.Close(); err != nil {
+					fmt.Fprintln(os.Stderr, "parallel: request scope:", err)
+				}
+			}()
+
+			next.ServeHTTP(w, r.WithContext(s.Context()))
+		})
+	}
+}
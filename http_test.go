@@ -0,0 +1,58 @@
+package parallel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestMiddlewareClientDisconnect(t *testing.T) {
+	var stopped int32
+	started := make(chan struct{})
+	h := parallel.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parallel.ScopeFrom(r.Context()).Go(func(ctx context.Context) {
+			<-ctx.Done()
+			atomic.AddInt32(&stopped, 1)
+		})
+		_, cancel := parallel.AllAsync(r.Context(), func(ctx context.Context) {
+			<-ctx.Done()
+			atomic.AddInt32(&stopped, 1)
+		})
+		defer cancel()
+
+		close(started)
+		//the client is gone
+		<-r.Context().Done()
+	}))
+
+	returned := make(chan int32, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		returned <- atomic.LoadInt32(&stopped)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Error("require the request to be canceled")
+	}
+
+	select {
+	case n := <-returned:
+		if n != 2 {
+			t.Error("work of the request outlived the handler", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("the handler did not see the disconnect")
+	}
+}
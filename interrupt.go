@@ -0,0 +1,81 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+//ErrInterrupted is returned by [ForInterruptible] and [ForEachInterruptible]
+//when SIGINT or SIGTERM is received
+var ErrInterrupted = errors.New("parallel: interrupted")
+
+//interruptContext returns a context that is canceled on SIGINT or SIGTERM or when parent is done.
+//the handler is removed when stop is called
+func interruptContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+//interruptLimiter is a [Limiter] that admits no iteration after ctx is done.
+//the loop stops starting iterations and waits for the running ones
+type interruptLimiter struct {
+	ctx context.Context
+}
+
+func (l interruptLimiter) Wait(ctx context.Context) error {
+	if err := l.ctx.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+//interruptErr returns the error of parent, or ErrInterrupted if only ctx is done
+func interruptErr(parent context.Context, ctx context.Context) error {
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ErrInterrupted
+	}
+	return nil
+}
+
+//ForInterruptible is [For] that stops on SIGINT or SIGTERM.
+//the signal handler is installed only for the duration of the call.
+//when a signal is received, no more iterations are started;
+//the iterations that are already running are not stopped and it returns ErrInterrupted after they finish
+//
+// if err := parallel.ForInterruptible(0, len(files), process); err != nil {
+// 		log.Fatal(err)
+// }
+func ForInterruptible(begin int, end int, f ForLoop) error {
+	return ForInterruptibleContext(context.Background(), begin, end, func(ctx context.Context, i int) {
+		f(i)
+	})
+}
+
+//ForInterruptibleContext is [ForInterruptible] with a context.
+//the context passed to f carries the Meta of the iteration like [ForContext]
+//and is canceled on SIGINT or SIGTERM or when c is done,
+//so the running iterations can stop early. it waits for them in both cases
+//and returns the error of c, or ErrInterrupted
+//
+// err := parallel.ForInterruptibleContext(ctx, 0, len(files), func(ctx context.Context, i int) {
+// 		process(ctx, files[i])
+// })
+func ForInterruptibleContext(c context.Context, begin int, end int, f ContextForLoop) error {
+	ctx, stop := interruptContext(c)
+	defer stop()
+
+	//the loop itself is not canceled so that it waits for the running iterations
+	ForContext(context.WithoutCancel(c), begin, end, func(iterCtx context.Context, i int) {
+		iterCtx, cancel := context.WithCancel(iterCtx)
+		defer cancel()
+		defer context.AfterFunc(ctx, cancel)()
+
+		f(iterCtx, i)
+	}, WithLimiter(interruptLimiter{ctx}))
+	return interruptErr(c, ctx)
+}
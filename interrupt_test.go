@@ -0,0 +1,51 @@
+package parallel_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForInterruptible(t *testing.T) {
+	var started, finished int32
+	err := parallel.ForInterruptible(0, 10, func(i int) {
+		atomic.AddInt32(&started, 1)
+		if i == 0 {
+			p, _ := os.FindProcess(os.Getpid())
+			p.Signal(os.Interrupt)
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&finished, 1)
+	})
+
+	if err != parallel.ErrInterrupted {
+		t.Error("require interrupted", err)
+	}
+	if s, f := atomic.LoadInt32(&started), atomic.LoadInt32(&finished); s != f {
+		t.Error("require waiting for the running iterations", s, f)
+	}
+}
+
+func TestForInterruptibleContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var finished int32
+	err := parallel.ForInterruptibleContext(ctx, 0, 4, func(ctx context.Context, i int) {
+		if _, ok := parallel.MetaFrom(ctx); !ok {
+			t.Error("require meta", i)
+		}
+		cancel()
+		<-ctx.Done()
+		atomic.AddInt32(&finished, 1)
+	})
+
+	if err != context.Canceled {
+		t.Error("require canceled", err)
+	}
+	if atomic.LoadInt32(&finished) == 0 {
+		t.Error("require running iterations to finish")
+	}
+}
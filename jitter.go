@@ -0,0 +1,25 @@
+package parallel
+
+import (
+	"context"
+	"time"
+)
+
+//WithStartJitter delays the start of each iteration by a random time in [0, max) from the start of the loop,
+//so a fan-out to a shared backend, such as cron jobs across a fleet, does not hit it all at the same instant.
+//the random numbers come from [WithRandSource] if it is given.
+//an iteration whose context is done while it waits is not started
+//
+// parallel.ForWithOptions(ctx, 0, len(hosts), ping, parallel.WithStartJitter(5*time.Second))
+func WithStartJitter(max time.Duration) Option {
+	return func(c *config) {
+		c.startJitter = max
+	}
+}
+
+//waitJitter waits until the start time of an iteration chosen by WithStartJitter.
+//it returns false if ctx is done first
+func (l *loop) waitJitter(ctx context.Context) bool {
+	offset := time.Duration(l.rand.Int63n(int64(l.jitter)))
+	return sleepWithContext(ctx, time.Until(l.start.Add(offset))) == nil
+}
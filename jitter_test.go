@@ -0,0 +1,48 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithStartJitter(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Duration
+	begin := time.Now()
+	parallel.ForWithOptions(context.Background(), 0, 20, func(i int) {
+		mu.Lock()
+		starts = append(starts, time.Since(begin))
+		mu.Unlock()
+	}, parallel.WithStartJitter(100*time.Millisecond))
+
+	if len(starts) != 20 {
+		t.Fatal("require 20", len(starts))
+	}
+	var late int
+	for _, s := range starts {
+		if s > 10*time.Millisecond {
+			late++
+		}
+	}
+	if late == 0 {
+		t.Error("require spread starts", starts)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Error("require starts within the window", elapsed)
+	}
+}
+
+func TestWithStartJitterCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	parallel.ForWithOptions(ctx, 0, 5, func(i int) {}, parallel.WithStartJitter(time.Hour))
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Error("require stop by context", elapsed)
+	}
+}
@@ -0,0 +1,51 @@
+package parallel
+
+import (
+	"context"
+)
+
+//ForEachJoinMaps calls f for each key of the union of the keys of a and b in parallel.
+//ok1 and ok2 report whether the key is in a and in b.
+//the key order can be set by [WithKeyLess]
+//
+// parallel.ForEachJoinMaps(want, have, func(k string, w int, inWant bool, h int, inHave bool) {
+// 		switch {
+// 		case !inHave:
+// 			fmt.Println("missing", k)
+// 		case !inWant:
+// 			fmt.Println("extra", k)
+// 		case w != h:
+// 			fmt.Println("changed", k)
+// 		}
+// })
+func ForEachJoinMaps[K comparable, A, B any](a map[K]A, b map[K]B, f func(k K, a A, ok1 bool, b B, ok2 bool), opts ...Option) {
+	ForEachJoinMapsWithContext(emptyContext, a, b, f, opts...)
+}
+
+//ForEachJoinMapsWithContext is [ForEachJoinMaps] that stops starting iterations when ctx is done
+func ForEachJoinMapsWithContext[K comparable, A, B any](ctx context.Context, a map[K]A, b map[K]B, f func(k K, a A, ok1 bool, b B, ok2 bool), opts ...Option) {
+	cfg := newConfig(opts)
+	keys := joinKeys(a, b)
+	keys = sortKeys(keys, cfg)
+
+	forRange(ctx, len(keys), func(i int) {
+		k := keys[i]
+		ea, ok1 := a[k]
+		eb, ok2 := b[k]
+		f(k, ea, ok1, eb, ok2)
+	}, cfg)
+}
+
+//joinKeys returns the union of the keys of a and b
+func joinKeys[K comparable, A, B any](a map[K]A, b map[K]B) []K {
+	keys := make([]K, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
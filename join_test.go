@@ -0,0 +1,40 @@
+package parallel_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForEachJoinMaps(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]string{"y": "two", "z": "three"}
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	parallel.ForEachJoinMaps(a, b, func(k string, ea int, ok1 bool, eb string, ok2 bool) {
+		mu.Lock()
+		seen[k] = true
+		mu.Unlock()
+
+		switch k {
+		case "x":
+			if !ok1 || ok2 || ea != 1 {
+				t.Error("bad x", ea, ok1, eb, ok2)
+			}
+		case "y":
+			if !ok1 || !ok2 || ea != 2 || eb != "two" {
+				t.Error("bad y", ea, ok1, eb, ok2)
+			}
+		case "z":
+			if ok1 || !ok2 || eb != "three" {
+				t.Error("bad z", ea, ok1, eb, ok2)
+			}
+		}
+	})
+
+	if len(seen) != 3 {
+		t.Error("require 3 keys", seen)
+	}
+}
@@ -0,0 +1,130 @@
+package parallel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//milliseconds returns d in milliseconds, the unit of the durations in JSON
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+//MarshalJSON encodes s with snake_case field names and the elapsed time in milliseconds
+//
+// {"run_id":"9f86d081884c7d65","total":10,"completed":9,"failed":1,"elapsed_ms":12.5}
+func (s Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RunID     string  `json:"run_id,omitempty"`
+		Total     int     `json:"total"`
+		Completed int     `json:"completed"`
+		Failed    int     `json:"failed"`
+		Elapsed   float64 `json:"elapsed_ms"`
+	}{s.RunID, s.Total, s.Completed, s.Failed, milliseconds(s.Elapsed)})
+}
+
+//MarshalJSON encodes r with snake_case field names and the durations in milliseconds
+//
+// {"requests":100,"errors":1,"error_counts":{"timeout":1},"elapsed_ms":1000,"throughput":100,
+// 	"mean_ms":3.2,"p50_ms":3,"p90_ms":5,"p99_ms":9,"max_ms":11}
+func (r Report) MarshalJSON() ([]byte, error) {
+	errorCounts := r.ErrorCounts
+	if errorCounts == nil {
+		errorCounts = map[string]int{}
+	}
+	return json.Marshal(struct {
+		Requests    int            `json:"requests"`
+		Errors      int            `json:"errors"`
+		ErrorCounts map[string]int `json:"error_counts"`
+		Elapsed     float64        `json:"elapsed_ms"`
+		Throughput  float64        `json:"throughput"`
+		Mean        float64        `json:"mean_ms"`
+		P50         float64        `json:"p50_ms"`
+		P90         float64        `json:"p90_ms"`
+		P99         float64        `json:"p99_ms"`
+		Max         float64        `json:"max_ms"`
+	}{
+		r.Requests, r.Errors, errorCounts, milliseconds(r.Elapsed), r.Throughput,
+		milliseconds(r.Mean), milliseconds(r.P50), milliseconds(r.P90), milliseconds(r.P99), milliseconds(r.Max),
+	})
+}
+
+//panicJSON is the JSON of a *PanicError
+type panicJSON struct {
+	Value string `json:"value"`
+	Stack string `json:"stack"`
+}
+
+//MarshalJSON encodes e with the value passed to panic formatted by fmt.Sprint
+//
+// {"value":"index out of range","stack":"goroutine 7 [running]:..."}
+func (e *PanicError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(panicJSON{fmt.Sprint(e.Value), string(e.Stack)})
+}
+
+//MarshalJSON encodes e with the message of its error.
+//the key is formatted by fmt.Sprint and omitted if it is nil,
+//and panic is set if the error is a *PanicError
+//
+// {"index":3,"key":"b","error":"parallel: panic: boom","panic":{"value":"boom","stack":"..."}}
+func (e *IterationError) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Index int         `json:"index"`
+		Key   *string     `json:"key,omitempty"`
+		Error string      `json:"error"`
+		Panic *PanicError `json:"panic,omitempty"`
+	}{Index: e.Index}
+	if e.Key != nil {
+		key := fmt.Sprint(e.Key)
+		v.Key = &key
+	}
+	if e.Err != nil {
+		v.Error = e.Err.Error()
+	}
+	errors.As(e.Err, &v.Panic)
+	return json.Marshal(v)
+}
+
+//MarshalJSON encodes the retained errors and the number of omitted errors
+//
+// {"errors":[{"index":1,"error":"not found"}],"omitted":0}
+func (e *Errors) MarshalJSON() ([]byte, error) {
+	items := e.Items
+	if items == nil {
+		items = []*IterationError{}
+	}
+	return json.Marshal(struct {
+		Errors  []*IterationError `json:"errors"`
+		Omitted int               `json:"omitted"`
+	}{items, e.Omitted})
+}
+
+//manifestJSON is the JSON of a ManifestEntry
+type manifestJSON struct {
+	RunID    string  `json:"run_id"`
+	Index    int     `json:"index"`
+	ID       string  `json:"id,omitempty"`
+	Hash     string  `json:"hash,omitempty"`
+	Duration float64 `json:"duration_ms"`
+	Outcome  string  `json:"outcome"`
+	Error    string  `json:"error,omitempty"`
+}
+
+//MarshalJSON encodes e with the duration in milliseconds
+//
+// {"run_id":"9f86d081884c7d65","index":3,"id":"a.csv","duration_ms":12.5,"outcome":"ok"}
+func (e ManifestEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(manifestJSON{e.RunID, e.Index, e.ID, e.Hash, milliseconds(e.Duration), e.Outcome, e.Error})
+}
+
+//UnmarshalJSON decodes a line written by [WithManifest]
+func (e *ManifestEntry) UnmarshalJSON(b []byte) error {
+	var v manifestJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*e = ManifestEntry{v.RunID, v.Index, v.ID, v.Hash, time.Duration(v.Duration * float64(time.Millisecond)), v.Outcome, v.Error}
+	return nil
+}
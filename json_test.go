@@ -0,0 +1,85 @@
+package parallel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestStatsJSON(t *testing.T) {
+	b, err := json.Marshal(parallel.Stats{Total: 3, Completed: 2, Failed: 1, Elapsed: 1500 * time.Microsecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != `{"total":3,"completed":2,"failed":1,"elapsed_ms":1.5}` {
+		t.Error(s)
+	}
+}
+
+func TestReportJSON(t *testing.T) {
+	b, err := json.Marshal(parallel.Report{Requests: 2, Elapsed: time.Second, Throughput: 2, P99: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"requests":2,"errors":0,"error_counts":{},"elapsed_ms":1000,"throughput":2,"mean_ms":0,"p50_ms":0,"p90_ms":0,"p99_ms":2,"max_ms":0}`
+	if s := string(b); s != want {
+		t.Error(s)
+	}
+}
+
+func TestErrorsJSON(t *testing.T) {
+	errNotFound := errors.New("not found")
+	err := parallel.ForErr(context.Background(), 0, 3, func(i int) error {
+		switch i {
+		case 1:
+			return errNotFound
+		case 2:
+			panic("boom")
+		}
+		return nil
+	}, parallel.WithCollectAllErrors(0))
+
+	b, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+
+	var v struct {
+		Errors []struct {
+			Index int     `json:"index"`
+			Key   *string `json:"key"`
+			Error string  `json:"error"`
+			Panic *struct {
+				Value string `json:"value"`
+				Stack string `json:"stack"`
+			} `json:"panic"`
+		} `json:"errors"`
+		Omitted int `json:"omitted"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Errors) != 2 || v.Omitted != 0 {
+		t.Fatal(string(b))
+	}
+	if e := v.Errors[0]; e.Index != 1 || e.Key != nil || e.Error != "not found" || e.Panic != nil {
+		t.Error(e)
+	}
+	if e := v.Errors[1]; e.Index != 2 || e.Panic == nil || e.Panic.Value != "boom" || e.Panic.Stack == "" {
+		t.Error(string(b))
+	}
+}
+
+func TestIterationErrorKeyJSON(t *testing.T) {
+	b, err := json.Marshal(&parallel.IterationError{Index: 0, Key: 42, Err: errors.New("bad")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != `{"index":0,"key":"42","error":"bad"}` {
+		t.Error(s)
+	}
+}
@@ -0,0 +1,59 @@
+package parallel
+
+import (
+	"fmt"
+	"sort"
+)
+
+//WithKeyLess makes the ForEachMap functions start the iterations in the order of less,
+//for example to process the largest tenants first.
+//a and b are keys of the map. the iterations still run in parallel,
+//but under limited concurrency the keys that start first also tend to finish first.
+//[WithKeyOrder] takes precedence over it
+//
+// parallel.ForEachMapOfWithOptions(ctx, tenants, f, parallel.WithKeyLess(func(a, b any) bool {
+// 		return tenants[a.(string)].Size > tenants[b.(string)].Size
+// }))
+func WithKeyLess(less func(a, b any) bool) Option {
+	return func(c *config) {
+		c.keyLess = less
+	}
+}
+
+//sortKeys returns keys in the order of c, like the keys of [ForEachMapWithOptions].
+//[WithKeyOrder] takes precedence over [WithKeyLess], and [WithKeyLess] over [WithSortedKeys].
+//if c has none of them, keys are returned as they are
+func sortKeys[K comparable](keys []K, c *config) []K {
+	if c.keyOrder != nil {
+		order, ok := c.keyOrder.([]K)
+		if !ok {
+			panic(fmt.Sprintf("map keys: %T but key order: %T", keys, c.keyOrder))
+		}
+		return orderedKeys(keys, order)
+	}
+	if c.keyLess != nil {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return c.keyLess(keys[i], keys[j])
+		})
+	} else if c.sortedKeys {
+		sortSlice(keys)
+	}
+	return keys
+}
+
+//orderedKeys returns the elements of order that are in keys.
+//a key repeated in order is returned once, at its first position
+func orderedKeys[K comparable](keys []K, order []K) []K {
+	in := make(map[K]bool, len(keys))
+	for _, k := range keys {
+		in[k] = true
+	}
+	ordered := make([]K, 0, len(order))
+	for _, k := range order {
+		if in[k] {
+			ordered = append(ordered, k)
+			delete(in, k)
+		}
+	}
+	return ordered
+}
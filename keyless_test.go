@@ -0,0 +1,48 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func descendingSize(sizes map[string]int) func(a, b any) bool {
+	return func(a, b any) bool {
+		return sizes[a.(string)] > sizes[b.(string)]
+	}
+}
+
+func TestWithKeyLessMapOf(t *testing.T) {
+	//a single worker starts and finishes the keys in order
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	sizes := map[string]int{"small": 1, "large": 100, "medium": 10}
+	var order []string
+	parallel.ForEachMapOfWithOptions(context.Background(), sizes, func(k string, v int) {
+		order = append(order, k)
+	}, parallel.WithKeyLess(descendingSize(sizes)), parallel.WithClaiming())
+
+	if len(order) != 3 || order[0] != "large" || order[1] != "medium" || order[2] != "small" {
+		t.Error("require [large medium small]", order)
+	}
+}
+
+func TestWithKeyLessMapOfErr(t *testing.T) {
+	sizes := map[string]int{"small": 1, "large": 100, "medium": 10}
+	err := parallel.ForEachMapOfErr(context.Background(), sizes, func(k string, v int) error {
+		return errors.New(k)
+	}, parallel.WithKeyLess(descendingSize(sizes)), parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 3 {
+		t.Fatal("require 3 errors", err)
+	}
+	for i, key := range []string{"large", "medium", "small"} {
+		if item := errs.Items[i]; item.Index != i || item.Key != key {
+			t.Error("bad order", i, item.Index, item.Key)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+//go:build !parallel_noreflect
+
+package parallel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+//WithSortedKeys makes the ForEachMap functions start the iterations in the order of sorted keys.
+//the iterations still run in parallel, but the order they are started in is stable.
+//the key type must be an integer, a float or a string
+func WithSortedKeys() Option {
+	return func(c *config) {
+		c.sortedKeys = true
+	}
+}
+
+//WithKeyOrder makes the ForEachMap functions start the iterations in the order of keys.
+//keys is a slice of the key type of the map.
+//only the keys in keys are visited, and keys that are not in the map are skipped
+//
+// parallel.ForEachMapWithOptions(ctx, m, f, parallel.WithKeyOrder([]string{"b", "a"}))
+func WithKeyOrder(keys interface{}) Option {
+	return func(c *config) {
+		c.keyOrder = keys
+	}
+}
+
+//mapKeys returns the keys of m in the order of c
+func (c *config) mapKeys(m reflect.Value) []reflect.Value {
+	if c.keyOrder != nil {
+		return orderedMapKeys(m, reflect.ValueOf(c.keyOrder))
+	}
+
+	keys := m.MapKeys()
+	if c.keyLess != nil {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return c.keyLess(keys[i].Interface(), keys[j].Interface())
+		})
+	} else if c.sortedKeys {
+		sortValues(keys)
+	}
+	return keys
+}
+
+//orderedMapKeys returns the elements of order that are keys of m.
+//a key repeated in order is returned once, at its first position
+func orderedMapKeys(m reflect.Value, order reflect.Value) []reflect.Value {
+	if keyType, elemType := m.Type().Key(), order.Type().Elem(); !elemType.AssignableTo(keyType) {
+		panic(fmt.Sprintf("map keyType: %v but key order type: %v", keyType, elemType))
+	}
+
+	keys := make([]reflect.Value, 0, order.Len())
+	seen := make(map[interface{}]bool, order.Len())
+	for i := 0; i < order.Len(); i++ {
+		key := order.Index(i)
+		if m.MapIndex(key).IsValid() && !seen[key.Interface()] {
+			seen[key.Interface()] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+//sortValues sorts values of an integer, float or string type.
+//values of an interface type are compared by their dynamic values,
+//and if their kinds are different or one of them is nil, they are sorted by fmt.Sprint
+func sortValues(values []reflect.Value) {
+	if len(values) == 0 {
+		return
+	}
+
+	kind := sortKind(values[0])
+	for _, v := range values[1:] {
+		if sortKind(v) != kind {
+			kind = reflect.Invalid
+		}
+	}
+	if kind == reflect.Invalid {
+		sortPrinted(values)
+		return
+	}
+
+	var less func(a, b reflect.Value) bool
+	switch kind {
+	case reflect.Int:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	default:
+		panic(fmt.Sprintf("%v keys can not be sorted", values[0].Type()))
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return less(dynamic(values[i]), dynamic(values[j]))
+	})
+}
+
+//sortKind returns the kind that sortValues compares v by:
+//reflect.Int, reflect.Uint, reflect.Float64 or reflect.String.
+//other kinds are returned as they are
+func sortKind(v reflect.Value) reflect.Kind {
+	switch k := dynamic(v).Kind(); k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	default:
+		return k
+	}
+}
+
+//dynamic returns the value in v if v is of an interface type
+func dynamic(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
+
+//sortPrinted sorts values of different kinds by fmt.Sprint, and then by their type
+func sortPrinted(values []reflect.Value) {
+	type printedValue struct {
+		value         reflect.Value
+		printed, kind string
+	}
+	printed := make([]printedValue, len(values))
+	for i, v := range values {
+		printed[i] = printedValue{v, fmt.Sprint(v.Interface()), fmt.Sprintf("%T", v.Interface())}
+	}
+	sort.Slice(printed, func(i, j int) bool {
+		if printed[i].printed != printed[j].printed {
+			return printed[i].printed < printed[j].printed
+		}
+		return printed[i].kind < printed[j].kind
+	})
+	for i, p := range printed {
+		values[i] = p.value
+	}
+}
+
+//sortSlice sorts the elements of the slice keys like [WithSortedKeys] sorts the keys of a map
+func sortSlice(keys interface{}) {
+	slice := reflect.ValueOf(keys)
+	values := make([]reflect.Value, slice.Len())
+	for i := range values {
+		values[i] = reflect.New(slice.Type().Elem()).Elem()
+		values[i].Set(slice.Index(i))
+	}
+	sortValues(values)
+	for i, v := range values {
+		slice.Index(i).Set(v)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build parallel_noreflect
+
+package parallel
+
+//sortSlice does nothing because [WithSortedKeys] needs reflection
+func sortSlice(keys interface{}) {}
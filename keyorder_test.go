@@ -0,0 +1,118 @@
+//go:build !parallel_noreflect
+
+package parallel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithSortedKeys(t *testing.T) {
+	m := reflect.ValueOf(map[int]string{3: "c", 1: "a", 2: "b", 5: "e", 4: "d"})
+	keys := newConfig([]Option{WithSortedKeys()}).mapKeys(m)
+
+	for i, k := range keys {
+		if k.Int() != int64(i+1) {
+			t.Error("require sorted keys", i, k)
+		}
+	}
+}
+
+func TestWithKeyOrder(t *testing.T) {
+	m := reflect.ValueOf(map[string]int{"a": 1, "b": 2, "c": 3})
+	keys := newConfig([]Option{WithKeyOrder([]string{"c", "x", "a"})}).mapKeys(m)
+
+	if len(keys) != 2 || keys[0].String() != "c" || keys[1].String() != "a" {
+		t.Error("require [c a]", keys)
+	}
+}
+
+func TestWithSortedKeysError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("struct keys can not be sorted")
+		}
+	}()
+	type key struct{ a int }
+	newConfig([]Option{WithSortedKeys()}).mapKeys(reflect.ValueOf(map[key]int{{1}: 1}))
+}
+
+func TestWithKeyLess(t *testing.T) {
+	m := reflect.ValueOf(map[int]string{3: "c", 1: "a", 2: "b", 5: "e", 4: "d"})
+	keys := newConfig([]Option{WithKeyLess(func(a, b any) bool {
+		return a.(int) > b.(int)
+	})}).mapKeys(m)
+
+	for i, k := range keys {
+		if k.Int() != int64(5-i) {
+			t.Error("require descending keys", i, k)
+		}
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	descending := WithKeyLess(func(a, b any) bool {
+		return a.(int) > b.(int)
+	})
+
+	keys := sortKeys([]int{3, 1, 2}, newConfig([]Option{WithSortedKeys()}))
+	if !reflect.DeepEqual(keys, []int{1, 2, 3}) {
+		t.Error("require sorted keys", keys)
+	}
+
+	keys = sortKeys([]int{3, 1, 2}, newConfig([]Option{WithSortedKeys(), descending}))
+	if !reflect.DeepEqual(keys, []int{3, 2, 1}) {
+		t.Error("WithKeyLess takes precedence over WithSortedKeys", keys)
+	}
+
+	keys = sortKeys([]int{3, 1, 2}, newConfig([]Option{WithKeyOrder([]int{2, 4, 3}), descending}))
+	if !reflect.DeepEqual(keys, []int{2, 3}) {
+		t.Error("WithKeyOrder takes precedence over WithKeyLess", keys)
+	}
+}
+
+func TestForEachMapOfKeyOrder(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	var order []string
+	ForEachMapOfWithOptions(emptyContext, m, func(k string, v int) {
+		order = append(order, k)
+	}, WithKeyOrder([]string{"c", "x", "a"}), WithSequential())
+
+	if !reflect.DeepEqual(order, []string{"c", "a"}) {
+		t.Error("require [c a]", order)
+	}
+}
+
+func TestWithKeyOrderDuplicate(t *testing.T) {
+	keys := sortKeys([]int{3, 1, 2}, newConfig([]Option{WithKeyOrder([]int{2, 3, 2, 4, 3})}))
+	if !reflect.DeepEqual(keys, []int{2, 3}) {
+		t.Error("require each key once", keys)
+	}
+}
+
+func TestWithKeyOrderDuplicateMap(t *testing.T) {
+	m := reflect.ValueOf(map[string]int{"a": 1, "b": 2, "c": 3})
+	keys := newConfig([]Option{WithKeyOrder([]string{"c", "a", "c", "a"})}).mapKeys(m)
+
+	if len(keys) != 2 || keys[0].String() != "c" || keys[1].String() != "a" {
+		t.Error("require [c a]", keys)
+	}
+}
+
+func TestWithSortedKeysInterface(t *testing.T) {
+	keys := sortKeys([]any{3, 1, 2}, newConfig([]Option{WithSortedKeys()}))
+	if !reflect.DeepEqual(keys, []any{1, 2, 3}) {
+		t.Error("require sorted keys", keys)
+	}
+
+	keys = sortKeys([]any{"b", 2, 1.5, "a", nil}, newConfig([]Option{WithSortedKeys()}))
+	if !reflect.DeepEqual(keys, []any{1.5, 2, nil, "a", "b"}) {
+		t.Error("require keys of different kinds sorted by fmt.Sprint", keys)
+	}
+
+	m := reflect.ValueOf(map[any]int{"b": 1, 2: 2, "a": 3})
+	mapKeys := newConfig([]Option{WithSortedKeys()}).mapKeys(m)
+	if len(mapKeys) != 3 || mapKeys[0].Interface() != 2 || mapKeys[1].Interface() != "a" || mapKeys[2].Interface() != "b" {
+		t.Error("require map keys of different kinds sorted by fmt.Sprint", mapKeys)
+	}
+}
@@ -0,0 +1,150 @@
+package parallel
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+//Leak is a goroutine started by a call of this package
+//that is still running after the call returned,
+//such as a loser of [Race] or an iteration of a loop whose context was canceled
+type Leak struct {
+	//Call is the name of the function that started the goroutine
+	Call string
+
+	//Index is the index of the task or the iteration
+	Index int
+
+	//Since is the time since the call returned
+	Since time.Duration
+
+	//Stack is the stack trace of the goroutine
+	Stack string
+}
+
+//leakSettings is the configuration of [DebugLeaks] and [SetLeakReporter]
+var leakSettings = struct {
+	mu      sync.Mutex
+	enabled bool
+	after   time.Duration
+	report  func(Leak)
+}{
+	after:  5 * time.Second,
+	report: printLeak,
+}
+
+//DebugLeaks turns the detection of leaked goroutines on or off.
+//when it is on, Race and the loops remember the goroutines they start
+//and report the ones that are still running some time after the call returned.
+//it costs a stack trace per goroutine, so use it in tests and debugging only
+//
+// func TestMain(m *testing.M) {
+// 		parallel.DebugLeaks(true)
+// 		os.Exit(m.Run())
+// }
+func DebugLeaks(enabled bool) {
+	leakSettings.mu.Lock()
+	leakSettings.enabled = enabled
+	leakSettings.mu.Unlock()
+}
+
+//SetLeakReporter makes [DebugLeaks] call report for each goroutine
+//that is still running after the call returned.
+//by default, leaks are checked 5 seconds after the call and written to os.Stderr.
+//if report is nil, they are written to os.Stderr
+func SetLeakReporter(after time.Duration, report func(Leak)) {
+	if report == nil {
+		report = printLeak
+	}
+	leakSettings.mu.Lock()
+	leakSettings.after = after
+	leakSettings.report = report
+	leakSettings.mu.Unlock()
+}
+
+//LeakReporter returns the settings of [SetLeakReporter],
+//so they can be restored after they are changed
+//
+// after, report := parallel.LeakReporter()
+// defer parallel.SetLeakReporter(after, report)
+func LeakReporter() (after time.Duration, report func(Leak)) {
+	leakSettings.mu.Lock()
+	defer leakSettings.mu.Unlock()
+	return leakSettings.after, leakSettings.report
+}
+
+func printLeak(l Leak) {
+	fmt.Fprintf(os.Stderr, "parallel: %s %d is still running %v after the call returned\n%s\n\n",
+		l.Call, l.Index, l.Since, l.Stack)
+}
+
+//leakTracker remembers the running goroutines of a call
+type leakTracker struct {
+	call    string
+	mu      sync.Mutex
+	running map[int]uint64
+}
+
+//newLeakTracker returns a tracker of the call named call.
+//if DebugLeaks is off, it returns nil
+func newLeakTracker(call string) *leakTracker {
+	leakSettings.mu.Lock()
+	enabled := leakSettings.enabled
+	leakSettings.mu.Unlock()
+
+	if !enabled {
+		return nil
+	}
+	return &leakTracker{
+		call:    call,
+		running: make(map[int]uint64),
+	}
+}
+
+//track registers index running in the goroutine of id
+//and returns the function that unregisters it
+func (t *leakTracker) track(index int, id uint64) func() {
+	t.mu.Lock()
+	t.running[index] = id
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		delete(t.running, index)
+		t.mu.Unlock()
+	}
+}
+
+//returned is called when the call returns.
+//it reports the goroutines that are still running after a while
+func (t *leakTracker) returned() {
+	leakSettings.mu.Lock()
+	after, report := leakSettings.after, leakSettings.report
+	leakSettings.mu.Unlock()
+
+	returnedAt := time.Now()
+	time.AfterFunc(after, func() {
+		t.mu.Lock()
+		running := make(map[int]uint64, len(t.running))
+		for index, id := range t.running {
+			running[index] = id
+		}
+		t.mu.Unlock()
+
+		if len(running) == 0 {
+			return
+		}
+		stacks := goroutineStacks()
+		since := time.Since(returnedAt)
+		for index, id := range running {
+			report(Leak{
+				Call:  t.call,
+				Index: index,
+				Since: since,
+				Stack: string(stacks[id]),
+			})
+		}
+	})
+}
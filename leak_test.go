@@ -0,0 +1,94 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+//setLeakReporter sets the leak reporter until the end of the test
+func setLeakReporter(t *testing.T, after time.Duration, report func(parallel.Leak)) {
+	prevAfter, prevReport := parallel.LeakReporter()
+	t.Cleanup(func() {
+		parallel.SetLeakReporter(prevAfter, prevReport)
+	})
+	parallel.SetLeakReporter(after, report)
+}
+
+func TestDebugLeaks(t *testing.T) {
+	var mu sync.Mutex
+	var leaks []parallel.Leak
+	parallel.DebugLeaks(true)
+	setLeakReporter(t, 50*time.Millisecond, func(l parallel.Leak) {
+		mu.Lock()
+		leaks = append(leaks, l)
+		mu.Unlock()
+	})
+	defer parallel.DebugLeaks(false)
+
+	release := make(chan struct{})
+	defer close(release)
+	parallel.Race(func() {
+	}, func() {
+		<-release
+	})
+
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(leaks) != 1 {
+		t.Fatal("require 1 leak", leaks)
+	}
+	if l := leaks[0]; l.Call != "Race task" || l.Index != 1 || l.Stack == "" {
+		t.Error("bad leak", l)
+	}
+}
+
+func TestDebugLeaksLoop(t *testing.T) {
+	var mu sync.Mutex
+	var leaks []parallel.Leak
+	parallel.DebugLeaks(true)
+	setLeakReporter(t, 50*time.Millisecond, func(l parallel.Leak) {
+		mu.Lock()
+		leaks = append(leaks, l)
+		mu.Unlock()
+	})
+	defer parallel.DebugLeaks(false)
+
+	release := make(chan struct{})
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	parallel.ForWithContext(ctx, 0, 3, func(i int) {
+		if i == 2 {
+			<-release
+		}
+	})
+
+	//a loop that finishes does not leak
+	parallel.For(0, 3, func(i int) {})
+
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(leaks) != 1 || leaks[0].Index != 2 {
+		t.Error("require the leak of 2", leaks)
+	}
+}
+
+func TestLeakReporter(t *testing.T) {
+	setLeakReporter(t, time.Millisecond, nil)
+	if a, r := parallel.LeakReporter(); a != time.Millisecond || r == nil {
+		t.Error("require the new reporter that writes to os.Stderr for nil", a)
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		setLeakReporter(t, time.Hour, func(parallel.Leak) {})
+	})
+	if a, _ := parallel.LeakReporter(); a != time.Millisecond {
+		t.Error("require the restored reporter", a)
+	}
+}
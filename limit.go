@@ -0,0 +1,126 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//Limiter decides when the next iteration may start.
+//Wait blocks until then or until ctx is done.
+//*rate.Limiter of golang.org/x/time/rate implements Limiter
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+//WithLimiter starts each iteration of the loop after l.Wait returns.
+//iterations that started still run concurrently
+//
+// parallel.ForWithOptions(ctx, 0, len(urls), f, parallel.WithLimiter(rate.NewLimiter(10, 1)))
+func WithLimiter(l Limiter) Option {
+	return func(c *config) {
+		c.limiter = l
+	}
+}
+
+//WithRateLimit starts at most n iterations of the loop per interval.
+//up to n iterations can start at once.
+//each call gets its own limit; to share a limit between loops, use [NewTokenBucket] with [WithLimiter].
+//it panics like [NewTokenBucket]
+//
+// parallel.ForWithOptions(ctx, 0, len(urls), f, parallel.WithRateLimit(100, time.Second))
+func WithRateLimit(n int, per time.Duration) Option {
+	checkRate(n, per)
+	return func(c *config) {
+		c.limiter = NewTokenBucket(n, per)
+	}
+}
+
+//TokenBucket is a [Limiter] that allows n iterations per interval.
+//it is safe for concurrent use, so a single TokenBucket passed to [WithLimiter]
+//caps the calls to a downstream service across all the loops that use it
+//
+// api := parallel.NewTokenBucket(100, time.Second)
+// go parallel.ForWithOptions(ctx, 0, len(a), fa, parallel.WithLimiter(api))
+// go parallel.ForWithOptions(ctx, 0, len(b), fb, parallel.WithLimiter(api))
+type TokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	tokens   float64
+	last     time.Time
+}
+
+//NewTokenBucket creates a full TokenBucket that holds up to n tokens and gets n tokens per interval.
+//it panics if n or per is not greater than 0, or if n is greater than the nanoseconds of per,
+//because a token is added at most every nanosecond
+func NewTokenBucket(n int, per time.Duration) *TokenBucket {
+	checkRate(n, per)
+	return newTokenBucket(per/time.Duration(n), n)
+}
+
+//checkRate panics if n tokens per interval can not be added
+func checkRate(n int, per time.Duration) {
+	if n <= 0 || per <= 0 {
+		panic("rate limit must be greater than 0")
+	}
+	if per/time.Duration(n) <= 0 {
+		panic(fmt.Sprintf("rate limit %d per %v is more than 1 per nanosecond", n, per))
+	}
+}
+
+//newTokenBucket creates a full TokenBucket that holds up to burst tokens and gets a token every interval
+func newTokenBucket(interval time.Duration, burst int) *TokenBucket {
+	return &TokenBucket{
+		interval: interval,
+		burst:    burst,
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+//refill adds the tokens since the last refill. r.mu must be held
+func (r *TokenBucket) refill() {
+	now := time.Now()
+	r.tokens += float64(now.Sub(r.last)) / float64(r.interval)
+	if r.tokens > float64(r.burst) {
+		r.tokens = float64(r.burst)
+	}
+	r.last = now
+}
+
+//Allow takes a token if there is one and reports whether it did
+func (r *TokenBucket) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refill()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+//Wait takes a token, waiting for it if there is none
+func (r *TokenBucket) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	r.refill()
+
+	//the token is reserved now, so concurrent waiters queue up
+	r.tokens--
+	var delay time.Duration
+	if r.tokens < 0 {
+		delay = time.Duration(-r.tokens * float64(r.interval))
+	}
+	r.mu.Unlock()
+
+	if err := sleepWithContext(ctx, delay); err != nil {
+		r.mu.Lock()
+		r.tokens++
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
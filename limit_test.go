@@ -0,0 +1,112 @@
+package parallel_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	begin := time.Now()
+	var count int32
+	parallel.ForWithOptions(context.Background(), 0, 10, func(i int) {
+		atomic.AddInt32(&count, 1)
+	}, parallel.WithRateLimit(5, 200*time.Millisecond))
+
+	//5 at once and 1 every 40ms after that
+	if elapsed := time.Since(begin); elapsed < 150*time.Millisecond {
+		t.Error("require rate limit", elapsed)
+	}
+	if count != 10 {
+		t.Error("require 10", count)
+	}
+}
+
+type countLimiter struct {
+	count int32
+}
+
+func (l *countLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.count, 1)
+	return ctx.Err()
+}
+
+func TestWithLimiter(t *testing.T) {
+	l := &countLimiter{}
+	parallel.ForWithOptions(context.Background(), 0, 10, func(i int) {
+	}, parallel.WithLimiter(l))
+
+	if l.count != 10 {
+		t.Error("require 10 waits", l.count)
+	}
+}
+
+func TestWithRateLimitContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var count int32
+	parallel.ForWithOptions(ctx, 0, 100, func(i int) {
+		atomic.AddInt32(&count, 1)
+	}, parallel.WithRateLimit(1, 50*time.Millisecond))
+
+	time.Sleep(100 * time.Millisecond)
+	if c := atomic.LoadInt32(&count); c > 5 {
+		t.Error("canceled loop must not start the rest", c)
+	}
+}
+
+func TestTokenBucketShared(t *testing.T) {
+	bucket := parallel.NewTokenBucket(5, 200*time.Millisecond)
+
+	begin := time.Now()
+	var count int32
+	parallel.All(func() {
+		parallel.ForWithOptions(context.Background(), 0, 5, func(i int) {
+			atomic.AddInt32(&count, 1)
+		}, parallel.WithLimiter(bucket))
+	}, func() {
+		parallel.ForWithOptions(context.Background(), 0, 5, func(i int) {
+			atomic.AddInt32(&count, 1)
+		}, parallel.WithLimiter(bucket))
+	})
+
+	//5 at once and 1 every 40ms after that across both loops
+	if elapsed := time.Since(begin); elapsed < 150*time.Millisecond {
+		t.Error("require shared rate limit", elapsed)
+	}
+	if count != 10 {
+		t.Error("require 10", count)
+	}
+}
+
+func TestTokenBucketAllow(t *testing.T) {
+	bucket := parallel.NewTokenBucket(2, time.Hour)
+	if !bucket.Allow() || !bucket.Allow() {
+		t.Error("require 2 tokens")
+	}
+	if bucket.Allow() {
+		t.Error("require empty bucket")
+	}
+}
+
+func TestTokenBucketTooFast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("require a panic for an interval under 1ns")
+		}
+	}()
+	parallel.NewTokenBucket(10, 5*time.Nanosecond)
+}
+
+func TestWithRateLimitTooFast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("require a panic for an interval under 1ns")
+		}
+	}()
+	parallel.WithRateLimit(1000, time.Microsecond/2)
+}
@@ -0,0 +1,165 @@
+package parallel
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+//LoadGen calls F repeatedly at a target rate and reports the throughput and the latency.
+//
+// report := parallel.LoadGen{
+// 		Rate:     100,
+// 		Duration: 10 * time.Second,
+// 		Workers:  16,
+// 		F: func(ctx context.Context) error {
+// 			return ping(ctx, url)
+// 		},
+// }.Run(ctx)
+// fmt.Println(report.Throughput, report.P99, report.Errors)
+type LoadGen struct {
+	//Rate is the target number of calls per second.
+	//if it is 0, the workers call F as fast as they can
+	Rate float64
+
+	//Duration is the length of the run.
+	//if it is 0, the run lasts until the context is done
+	Duration time.Duration
+
+	//Workers is the number of goroutines that call F.
+	//if it is 0, runtime.GOMAXPROCS(0) is used.
+	//when all workers are busy, the rate falls below Rate
+	Workers int
+
+	//F is the function under load
+	F func(ctx context.Context) error
+}
+
+//Report is the result of [LoadGen.Run]
+type Report struct {
+	//Requests is the number of calls of F that finished in the run
+	Requests int
+
+	//Errors is the number of calls that returned an error
+	Errors int
+
+	//ErrorCounts is the number of calls by the message of the error
+	ErrorCounts map[string]int
+
+	//Elapsed is the length of the run
+	Elapsed time.Duration
+
+	//Throughput is Requests per second
+	Throughput float64
+
+	//Mean, P50, P90, P99 and Max are the latencies of the calls
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+//Run calls F until Duration has elapsed or ctx is done.
+//a call that fails because the run ended is not counted
+func (g LoadGen) Run(ctx context.Context) Report {
+	if g.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Duration)
+		defer cancel()
+	}
+
+	var limiter Limiter
+	if g.Rate > 0 {
+		limiter = newTokenBucket(time.Duration(float64(time.Second)/g.Rate), 1)
+	}
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	errorCounts := make(map[string]int)
+
+	begin := time.Now()
+	startWorkers(g.Workers, func() {
+		var local []time.Duration
+		localErrors := make(map[string]int)
+		defer func() {
+			mu.Lock()
+			latencies = append(latencies, local...)
+			for msg, n := range localErrors {
+				errorCounts[msg] += n
+			}
+			mu.Unlock()
+		}()
+
+		for ctx.Err() == nil {
+			if limiter != nil && limiter.Wait(ctx) != nil {
+				return
+			}
+			start := time.Now()
+			err := g.call(ctx)
+			latency := time.Since(start)
+			if err != nil && ctx.Err() != nil {
+				//cut off by the end of the run
+				return
+			}
+
+			local = append(local, latency)
+			if err != nil {
+				localErrors[err.Error()]++
+			}
+		}
+	}).Wait()
+
+	return newReport(latencies, errorCounts, time.Since(begin))
+}
+
+//call calls F. a panic of F is returned as *PanicError
+func (g LoadGen) call(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return g.F(ctx)
+}
+
+func newReport(latencies []time.Duration, errorCounts map[string]int, elapsed time.Duration) Report {
+	r := Report{
+		Requests:    len(latencies),
+		ErrorCounts: errorCounts,
+		Elapsed:     elapsed,
+	}
+	for _, n := range errorCounts {
+		r.Errors += n
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	}
+	if len(latencies) == 0 {
+		return r
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+	r.Mean = sum / time.Duration(len(latencies))
+	r.P50 = percentile(latencies, 50)
+	r.P90 = percentile(latencies, 90)
+	r.P99 = percentile(latencies, 99)
+	r.Max = latencies[len(latencies)-1]
+	return r
+}
+
+//percentile returns the p-th percentile of sorted by the nearest rank
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,59 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestLoadGen(t *testing.T) {
+	var calls int32
+	report := parallel.LoadGen{
+		Rate:     100,
+		Duration: 200 * time.Millisecond,
+		Workers:  4,
+		F: func(ctx context.Context) error {
+			if atomic.AddInt32(&calls, 1)%2 == 0 {
+				return errors.New("even")
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+	}.Run(context.Background())
+
+	//about 20 calls in 200ms at 100 per second
+	if report.Requests < 5 || report.Requests > 40 {
+		t.Error("bad requests", report.Requests)
+	}
+	if report.Errors == 0 || report.ErrorCounts["even"] != report.Errors {
+		t.Error("bad errors", report.Errors, report.ErrorCounts)
+	}
+	if report.P50 > report.P99 || report.P99 > report.Max || report.Max == 0 {
+		t.Error("bad latency", report.P50, report.P99, report.Max)
+	}
+	if report.Throughput <= 0 {
+		t.Error("bad throughput", report.Throughput)
+	}
+}
+
+func TestLoadGenContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report := parallel.LoadGen{
+		Workers: 2,
+		F: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}.Run(ctx)
+
+	//the calls cut off by the end of the run are not counted
+	if report.Requests != 0 || report.Errors != 0 {
+		t.Error("require no requests", report.Requests, report.Errors)
+	}
+}
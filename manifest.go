@@ -0,0 +1,141 @@
+package parallel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+//ManifestEntry is a line of the manifest written by [WithManifest]
+type ManifestEntry struct {
+	//RunID is the [RunID] of the loop. it is set by the loop
+	RunID string `json:"run_id"`
+
+	//Index is the index of the iteration. it is set by the loop
+	Index int `json:"index"`
+
+	//ID identifies the item, such as a file name or a record key
+	ID string `json:"id,omitempty"`
+
+	//Hash is the hash of the content of the item, such as a hex SHA-256
+	Hash string `json:"hash,omitempty"`
+
+	//Duration is the duration of the iteration. it is set by the loop.
+	//it is written in milliseconds like the durations of [Stats]
+	Duration time.Duration `json:"duration_ms"`
+
+	//Outcome is "ok", "error" or "panic". it is set by the loop
+	Outcome string `json:"outcome"`
+
+	//Error is the message of the error of the iteration. it is set by the loop
+	Error string `json:"error,omitempty"`
+}
+
+//WithManifest writes a JSON line to w for each finished iteration of the loop
+//with its duration, its outcome and the ID and Hash returned by describe,
+//so the items processed by a data pipeline can be audited.
+//describe is called after the iteration is finished and may be nil.
+//the lines are written in the order the iterations finish.
+//if writing to w fails, the error is printed to os.Stderr and the rest of the manifest is not written
+//
+// err := parallel.ForErr(ctx, 0, len(files), convert, parallel.WithManifest(out, func(i int) parallel.ManifestEntry {
+// 		return parallel.ManifestEntry{ID: files[i], Hash: hashes[i]}
+// }))
+func WithManifest(w io.Writer, describe func(i int) ManifestEntry) Option {
+	return func(c *config) {
+		c.manifest = &manifestWriter{w: w, describe: describe}
+	}
+}
+
+//manifestWriter is the Monitor that writes the manifest
+type manifestWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	describe func(i int) ManifestEntry
+	runID    string
+	failed   bool
+}
+
+//forRun returns the manifestWriter of the loop of runID
+func (m *manifestWriter) forRun(runID string) *manifestWriter {
+	return &manifestWriter{w: m.w, describe: m.describe, runID: runID}
+}
+
+func (m *manifestWriter) OnStart(total int) {
+}
+
+func (m *manifestWriter) OnItemDone(i int, d time.Duration, err error) {
+	var e ManifestEntry
+	if m.describe != nil {
+		callRecover(func() {
+			e = m.describe(i)
+		})
+	}
+	e.RunID = m.runID
+	e.Index = i
+	e.Duration = d
+	e.Outcome = "ok"
+	if err != nil {
+		e.Outcome = "error"
+		var p *PanicError
+		if errors.As(err, &p) {
+			e.Outcome = "panic"
+		}
+		e.Error = err.Error()
+	}
+
+	line, jsonErr := json.Marshal(e)
+	if jsonErr != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failed {
+		return
+	}
+	if _, err := m.w.Write(line); err != nil {
+		m.failed = true
+		fmt.Fprintln(os.Stderr, "parallel: manifest:", err)
+	}
+}
+
+func (m *manifestWriter) OnFinish(stats Stats) {
+}
+
+//monitors is a Monitor that notifies each of its monitors
+type monitors []Monitor
+
+func (ms monitors) OnStart(total int) {
+	for _, m := range ms {
+		m.OnStart(total)
+	}
+}
+
+func (ms monitors) OnItemDone(i int, d time.Duration, err error) {
+	for _, m := range ms {
+		m.OnItemDone(i, d, err)
+	}
+}
+
+func (ms monitors) OnFinish(stats Stats) {
+	for _, m := range ms {
+		m.OnFinish(stats)
+	}
+}
+
+//loopMonitor returns the Monitor of the loop of runID configured by c, or nil
+func (c *config) loopMonitor(runID string) Monitor {
+	if c.manifest == nil {
+		return c.monitor
+	}
+	if c.monitor == nil {
+		return c.manifest.forRun(runID)
+	}
+	return monitors{c.monitor, c.manifest.forRun(runID)}
+}
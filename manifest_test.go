@@ -0,0 +1,96 @@
+package parallel_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func readManifest(t *testing.T, b *bytes.Buffer) []parallel.ManifestEntry {
+	t.Helper()
+	var entries []parallel.ManifestEntry
+	s := bufio.NewScanner(b)
+	for s.Scan() {
+		var e parallel.ManifestEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Index < entries[j].Index
+	})
+	return entries
+}
+
+func TestWithManifest(t *testing.T) {
+	var b bytes.Buffer
+	errOdd := errors.New("odd")
+	parallel.ForErr(context.Background(), 0, 4, func(i int) error {
+		if i == 3 {
+			panic("three")
+		}
+		if i%2 == 1 {
+			return errOdd
+		}
+		return nil
+	}, parallel.WithCollectAllErrors(0), parallel.WithManifest(&b, func(i int) parallel.ManifestEntry {
+		return parallel.ManifestEntry{ID: fmt.Sprint("item", i), Hash: "h"}
+	}))
+
+	entries := readManifest(t, &b)
+	if len(entries) != 4 {
+		t.Fatal(entries)
+	}
+	outcomes := []string{"ok", "error", "ok", "panic"}
+	for i, e := range entries {
+		if e.Index != i || e.ID != fmt.Sprint("item", i) || e.Hash != "h" {
+			t.Error(e)
+		}
+		if e.Outcome != outcomes[i] {
+			t.Error(i, e.Outcome)
+		}
+		if (e.Outcome == "ok") != (e.Error == "") {
+			t.Error(e)
+		}
+	}
+}
+
+func TestManifestEntryJSON(t *testing.T) {
+	e := parallel.ManifestEntry{RunID: "r", Index: 3, ID: "a.csv", Duration: 12500 * time.Microsecond, Outcome: "ok"}
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != `{"run_id":"r","index":3,"id":"a.csv","duration_ms":12.5,"outcome":"ok"}` {
+		t.Error(s)
+	}
+
+	var decoded parallel.ManifestEntry
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded != e {
+		t.Error(decoded, err)
+	}
+}
+
+func TestWithManifestAndMonitor(t *testing.T) {
+	var b bytes.Buffer
+	m := &recordMonitor{}
+	parallel.ForErr(context.Background(), 0, 3, func(i int) error {
+		return nil
+	}, parallel.WithManifest(&b, nil), parallel.WithMonitor(m))
+
+	if entries := readManifest(t, &b); len(entries) != 3 {
+		t.Error(entries)
+	}
+	if len(m.done) != 3 || m.finish == nil {
+		t.Error(m.done)
+	}
+}
@@ -0,0 +1,39 @@
+package parallel
+
+//MapMap calls f for each key and value of m in parallel
+//and returns the results. the order of the results is the order of range m
+//
+// lines := parallel.MapMap(m, func(k string, v int) string {
+// 		return fmt.Sprint(k, "=", v)
+// })
+func MapMap[K comparable, V, R any](m map[K]V, f func(K, V) R, opts ...Option) []R {
+	type entry struct {
+		k K
+		v V
+	}
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, entry{k, v})
+	}
+	return Map(entries, func(e entry) R {
+		return f(e.k, e.v)
+	}, opts...)
+}
+
+//MapKeys calls f for each key of m in parallel and returns the results
+func MapKeys[K comparable, V, R any](m map[K]V, f func(K) R, opts ...Option) []R {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return Map(keys, f, opts...)
+}
+
+//MapValues calls f for each value of m in parallel and returns the results
+func MapValues[K comparable, V, R any](m map[K]V, f func(V) R, opts ...Option) []R {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return Map(values, f, opts...)
+}
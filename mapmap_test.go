@@ -0,0 +1,37 @@
+package parallel_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestMapMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	lines := parallel.MapMap(m, func(k string, v int) string {
+		return fmt.Sprint(k, "=", v)
+	})
+
+	sort.Strings(lines)
+	if fmt.Sprint(lines) != "[a=1 b=2 c=3]" {
+		t.Error("require [a=1 b=2 c=3]", lines)
+	}
+}
+
+func TestMapKeysValues(t *testing.T) {
+	m := map[string]int{"a": 1, "bb": 2, "ccc": 3}
+	lens := parallel.MapKeys(m, func(k string) int {
+		return len(k)
+	})
+	doubles := parallel.MapValues(m, func(v int) int {
+		return v * 2
+	})
+
+	sort.Ints(lens)
+	sort.Ints(doubles)
+	if fmt.Sprint(lens) != "[1 2 3]" || fmt.Sprint(doubles) != "[2 4 6]" {
+		t.Error("bad results", lens, doubles)
+	}
+}
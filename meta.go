@@ -0,0 +1,162 @@
+package parallel
+
+import (
+	"context"
+	"log/slog"
+)
+
+//metaKey is the context key of Meta.
+//it is not exported, use [MetaFrom] to read Meta
+type metaKey struct{}
+
+//Meta is the information about the current iteration
+//that is carried by the context passed to callbacks.
+//new fields may be added, but existing fields are not removed or changed
+type Meta struct {
+	//Index is the index of the current iteration
+	Index int
+
+	//Begin is the first index of the loop
+	Begin int
+
+	//End is the index after the last index of the loop
+	End int
+
+	//Worker is the number of the worker that calls the iteration, from 0.
+	//with [WithWorkers], [WithClaiming] or [WithSequential] the iterations of a worker
+	//run one after another, so it can index the state of each worker.
+	//it is -1 if the iteration runs in its own goroutine
+	//or if f is called by reflection, such as the function of [ForEach]
+	Worker int
+
+	runID  string
+	loop   *loop
+	logger *slog.Logger
+}
+
+//Stats returns the Stats of the loop so far.
+//it is the zero Stats if f is called by reflection, such as the function of [ForEach]
+func (m Meta) Stats() Stats {
+	if m.loop == nil {
+		return Stats{}
+	}
+	return m.loop.stats()
+}
+
+//Logger returns the logger of [WithLogger], or slog.Default(),
+//with the run id, the index and the worker of the iteration
+//
+// parallel.ForContext(ctx, 0, len(s), func(ctx context.Context, i int) {
+// 		m, _ := parallel.MetaFrom(ctx)
+// 		m.Logger().Info("start")
+// }, parallel.WithLogger(logger))
+func (m Meta) Logger() *slog.Logger {
+	logger := m.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With(slog.String("run_id", m.runID), slog.Int("index", m.Index), slog.Int("worker", m.Worker))
+}
+
+//WithLogger sets the logger returned by [Meta.Logger]
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+//MetaFrom returns the Meta carried by ctx.
+//if ctx was not passed by this package, ok is false
+func MetaFrom(ctx context.Context) (m Meta, ok bool) {
+	m, ok = ctx.Value(metaKey{}).(Meta)
+	return
+}
+
+func contextWithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+//iterationContext returns the context passed to the iteration of m
+//and the function that must be called when the iteration returns
+func iterationContext(c context.Context, m Meta, cfg *config) (context.Context, func()) {
+	m.runID = RunID(c)
+	m.logger = cfg.logger
+	ctx := contextWithMeta(c, m)
+	if cfg.traceIDs != nil {
+		ctx = ContextWithTraceID(ctx, cfg.traceIDs(m.Index))
+	}
+	if cfg.scratch != nil {
+		return contextWithScratch(ctx, cfg.scratch)
+	}
+	return ctx, func() {}
+}
+
+//ContextForLoop type is used in the ForContext function
+type ContextForLoop func(ctx context.Context, i int)
+
+//ForContext function repeats in parallel, starting with begin and ending with end.
+//Internally, it call the ContextForLoop function each loop
+//with a context that carries the Meta of the iteration,
+//the trace id of [WithTraceIDs] and the value of [WithScratch]
+//
+// parallel.ForContext(ctx, 0, 10, func(ctx context.Context, i int) {
+// 		m, _ := parallel.MetaFrom(ctx)
+// 		fmt.Println(m.Index, m.End)
+// })
+func ForContext(c context.Context, begin int, end int, f ContextForLoop, opts ...Option) {
+	forContextWithConfig(c, begin, end, func(ctx context.Context, i int) error {
+		f(ctx, i)
+		return nil
+	}, newConfig(opts))
+}
+
+//ContextErrLoop type is used in the ForErrContext function
+type ContextErrLoop func(ctx context.Context, i int) error
+
+//ForErrContext is [ForContext] that returns the errors of the iterations like [ForErr]
+//
+// err := parallel.ForErrContext(ctx, 0, len(urls), func(ctx context.Context, i int) error {
+// 		return fetch(ctx, urls[i])
+// }, parallel.WithWorkers(8))
+func ForErrContext(c context.Context, begin int, end int, f ContextErrLoop, opts ...Option) error {
+	cfg := newConfig(opts)
+	errs := newErrorCollector("iteration", cfg)
+	forContextWithConfig(c, begin, end, func(ctx context.Context, i int) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+			err = errs.add(i, nil, err)
+		}()
+
+		return f(ctx, i)
+	}, cfg)
+
+	if err := c.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+//forContextWithConfig calls f with the context of each iteration configured by cfg
+func forContextWithConfig(c context.Context, begin int, end int, f ContextErrLoop, cfg *config) {
+	c = withRunID(c)
+
+	var stalls *stallDetector
+	if cfg.stall != nil && cfg.stall.timeout > 0 {
+		stalls = startStallDetector(cfg.stall)
+		defer stalls.close()
+	}
+
+	l := newLoop(RunID(c), begin, end, cfg)
+	runLoop(c, l, func(worker int, i int) error {
+		ctx, done := iterationContext(c, Meta{Index: i, Begin: begin, End: end, Worker: worker, loop: l}, cfg)
+		defer done()
+		if stalls != nil {
+			var untrack func()
+			ctx, untrack = stalls.track(ctx, i)
+			defer untrack()
+		}
+		return f(ctx, i)
+	}, cfg)
+}
@@ -0,0 +1,101 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForContextMeta(t *testing.T) {
+	parallel.ForContext(context.Background(), 3, 10, func(ctx context.Context, i int) {
+		m, ok := parallel.MetaFrom(ctx)
+		if !ok {
+			t.Error("require meta")
+		}
+		if m.Index != i || m.Begin != 3 || m.End != 10 {
+			t.Error("bad meta", i, m)
+		}
+	})
+}
+
+func TestMetaFromEmpty(t *testing.T) {
+	if _, ok := parallel.MetaFrom(context.Background()); ok {
+		t.Error("background context must not have meta")
+	}
+}
+
+func TestForErrContext(t *testing.T) {
+	errOdd := errors.New("odd")
+	err := parallel.ForErrContext(context.Background(), 0, 4, func(ctx context.Context, i int) error {
+		if m, ok := parallel.MetaFrom(ctx); !ok || m.Index != i {
+			t.Error(m)
+		}
+		if i%2 == 1 {
+			return errOdd
+		}
+		return nil
+	}, parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 2 || !errors.Is(err, errOdd) {
+		t.Error(err)
+	}
+}
+
+func TestMetaWorker(t *testing.T) {
+	var mu sync.Mutex
+	workers := map[int]bool{}
+	parallel.ForContext(context.Background(), 0, 100, func(ctx context.Context, i int) {
+		m, _ := parallel.MetaFrom(ctx)
+		mu.Lock()
+		workers[m.Worker] = true
+		mu.Unlock()
+	}, parallel.WithWorkers(2))
+	for w := range workers {
+		if w != 0 && w != 1 {
+			t.Error("require the worker 0 or 1", w)
+		}
+	}
+
+	parallel.ForContext(context.Background(), 0, 3, func(ctx context.Context, i int) {
+		if m, _ := parallel.MetaFrom(ctx); m.Worker != -1 {
+			t.Error("require -1 without workers", m.Worker)
+		}
+	})
+}
+
+func TestMetaStats(t *testing.T) {
+	errOdd := errors.New("odd")
+	var last parallel.Stats
+	parallel.ForErrContext(context.Background(), 0, 4, func(ctx context.Context, i int) error {
+		m, _ := parallel.MetaFrom(ctx)
+		last = m.Stats()
+		if i == 1 {
+			return errOdd
+		}
+		return nil
+	}, parallel.WithSequential())
+	if last.Total != 4 || last.Completed != 2 || last.Failed != 1 || last.RunID == "" {
+		t.Error(last)
+	}
+}
+
+func TestMetaLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ctx := parallel.ContextWithRunID(context.Background(), "run")
+	parallel.ForContext(ctx, 5, 6, func(ctx context.Context, i int) {
+		m, _ := parallel.MetaFrom(ctx)
+		m.Logger().Info("hello")
+	}, parallel.WithLogger(logger))
+
+	if s := buf.String(); !strings.Contains(s, "msg=hello run_id=run index=5 worker=-1") {
+		t.Error(s)
+	}
+}
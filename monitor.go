@@ -0,0 +1,123 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//Monitor is notified of the progress of a loop.
+//OnItemDone is called concurrently from the goroutines of the iterations
+type Monitor interface {
+	//OnStart is called before the first iteration starts
+	OnStart(total int)
+
+	//OnItemDone is called when the iteration i is finished.
+	//d is the duration of the iteration and err is its error.
+	//if the iteration panics, err is a *PanicError
+	OnItemDone(i int, d time.Duration, err error)
+
+	//OnFinish is called after all started iterations are finished
+	OnFinish(stats Stats)
+}
+
+//Stats is the summary of a loop passed to [Monitor.OnFinish]
+type Stats struct {
+	//RunID is the [RunID] of the loop
+	RunID string
+
+	//Total is the number of iterations of the loop
+	Total int
+
+	//Completed is the number of iterations that are finished without error
+	Completed int
+
+	//Failed is the number of iterations that are finished with error
+	Failed int
+
+	//Elapsed is the time since the loop started
+	Elapsed time.Duration
+}
+
+//WithMonitor notifies m of the progress of the loop
+//
+// p := &parallel.Progress{}
+// go parallel.ForWithOptions(ctx, 0, len(s), f, parallel.WithMonitor(p))
+// fmt.Println(p.Completed(), "/", p.Total())
+func WithMonitor(m Monitor) Option {
+	return func(c *config) {
+		c.monitor = m
+	}
+}
+
+//stats returns the Stats of l
+func (l *loop) stats() Stats {
+	finished := int(atomic.LoadInt64(&l.finished))
+	failed := int(atomic.LoadInt64(&l.failed))
+	return Stats{
+		RunID:     l.runID,
+		Total:     l.len(),
+		Completed: finished - failed,
+		Failed:    failed,
+		Elapsed:   time.Since(l.start),
+	}
+}
+
+//Progress is a Monitor that counts the iterations.
+//the counts only grow, so they can be exported as counters of metrics.
+//the zero value is ready to use and it is safe for concurrent use
+type Progress struct {
+	total     int64
+	completed int64
+	failed    int64
+	start     int64
+	finish    int64
+}
+
+//OnStart implements Monitor
+func (p *Progress) OnStart(total int) {
+	atomic.AddInt64(&p.total, int64(total))
+	atomic.CompareAndSwapInt64(&p.start, 0, time.Now().UnixNano())
+}
+
+//OnItemDone implements Monitor
+func (p *Progress) OnItemDone(i int, d time.Duration, err error) {
+	if err != nil {
+		atomic.AddInt64(&p.failed, 1)
+	} else {
+		atomic.AddInt64(&p.completed, 1)
+	}
+}
+
+//OnFinish implements Monitor
+func (p *Progress) OnFinish(stats Stats) {
+	atomic.StoreInt64(&p.finish, time.Now().UnixNano())
+}
+
+//Total returns the number of iterations of the monitored loops
+func (p *Progress) Total() int64 {
+	return atomic.LoadInt64(&p.total)
+}
+
+//Completed returns the number of iterations that are finished without error
+func (p *Progress) Completed() int64 {
+	return atomic.LoadInt64(&p.completed)
+}
+
+//Failed returns the number of iterations that are finished with error
+func (p *Progress) Failed() int64 {
+	return atomic.LoadInt64(&p.failed)
+}
+
+//Elapsed returns the time since the first loop started.
+//after the loop is finished, it is the time until it finished
+func (p *Progress) Elapsed() time.Duration {
+	start := atomic.LoadInt64(&p.start)
+	if start == 0 {
+		return 0
+	}
+	end := atomic.LoadInt64(&p.finish)
+	if end == 0 {
+		end = time.Now().UnixNano()
+	}
+	return time.Duration(end - start)
+}
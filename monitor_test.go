@@ -0,0 +1,76 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+type recordMonitor struct {
+	mu     sync.Mutex
+	total  int
+	done   []int
+	errs   int
+	finish *parallel.Stats
+}
+
+func (m *recordMonitor) OnStart(total int) {
+	m.total = total
+}
+
+func (m *recordMonitor) OnItemDone(i int, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.done = append(m.done, i)
+	if err != nil {
+		m.errs++
+	}
+}
+
+func (m *recordMonitor) OnFinish(stats parallel.Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finish = &stats
+}
+
+func TestWithMonitorFor(t *testing.T) {
+	m := &recordMonitor{}
+	parallel.ForWithOptions(context.Background(), 0, 10, func(i int) {
+		if i == 3 {
+			panic("hello")
+		}
+	}, parallel.WithMonitor(m))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.total != 10 || len(m.done) != 10 || m.errs != 1 {
+		t.Error("bad monitor", m.total, m.done, m.errs)
+	}
+	if m.finish == nil || m.finish.Completed != 9 || m.finish.Failed != 1 {
+		t.Error("bad stats", m.finish)
+	}
+}
+
+func TestWithMonitorAll(t *testing.T) {
+	p := &parallel.Progress{}
+	err := parallel.AllWithOptions(context.Background(), []parallel.TaskFunc{
+		func() {},
+		func() {},
+		func() { panic("fail") },
+	}, parallel.WithMonitor(p))
+
+	var panicErr *parallel.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Error("require panic error", err)
+	}
+	if p.Total() != 3 || p.Completed() != 2 || p.Failed() != 1 {
+		t.Error("bad progress", p.Total(), p.Completed(), p.Failed())
+	}
+	if p.Elapsed() <= 0 {
+		t.Error("require elapsed")
+	}
+}
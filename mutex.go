@@ -0,0 +1,242 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+//waitState is a lock state guarded by mu.
+//waiters wait on changed, which is closed and replaced when the state changes
+type waitState struct {
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+//waitChan returns the channel that is closed at the next change.
+//s.mu must be held
+func (s *waitState) waitChan() chan struct{} {
+	if s.changed == nil {
+		s.changed = make(chan struct{})
+	}
+	return s.changed
+}
+
+//notify wakes up the waiters. s.mu must be held
+func (s *waitState) notify() {
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+}
+
+//acquire waits until try returns true or ctx is done.
+//try is called with s.mu held
+func (s *waitState) acquire(ctx context.Context, try func() bool) error {
+	for {
+		s.mu.Lock()
+		if try() {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.waitChan()
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//CtxMutex is a mutual exclusion lock whose Lock gives up when a context is done,
+//so callbacks waiting for shared state still honor the cancellation of the loop.
+//the zero value is an unlocked mutex
+//
+// var mu parallel.CtxMutex
+// parallel.ForContext(ctx, 0, n, func(ctx context.Context, i int) {
+// 		if err := mu.Lock(ctx); err != nil {
+// 			return
+// 		}
+// 		defer mu.Unlock()
+// 		shared[i%10]++
+// })
+type CtxMutex struct {
+	state  waitState
+	locked bool
+}
+
+//Lock locks m. if ctx is done first, it returns ctx.Err() and m is not locked
+func (m *CtxMutex) Lock(ctx context.Context) error {
+	return m.state.acquire(ctx, m.tryLocked)
+}
+
+//TryLock locks m if it is not locked and reports whether it did
+func (m *CtxMutex) TryLock() bool {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	return m.tryLocked()
+}
+
+//tryLocked is TryLock with m.state.mu held
+func (m *CtxMutex) tryLocked() bool {
+	if m.locked {
+		return false
+	}
+	m.locked = true
+	return true
+}
+
+//Unlock unlocks m. it panics if m is not locked
+func (m *CtxMutex) Unlock() {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	if !m.locked {
+		panic("parallel: unlock of unlocked CtxMutex")
+	}
+	m.locked = false
+	m.state.notify()
+}
+
+//CtxRWMutex is a reader/writer lock whose Lock and RLock give up when a context is done.
+//a waiting writer blocks new readers, so writers are not starved.
+//the zero value is an unlocked mutex
+type CtxRWMutex struct {
+	state          waitState
+	readers        int
+	writer         bool
+	waitingWriters int
+}
+
+//Lock locks m for writing. if ctx is done first, it returns ctx.Err() and m is not locked
+func (m *CtxRWMutex) Lock(ctx context.Context) error {
+	m.state.mu.Lock()
+	m.waitingWriters++
+	m.state.mu.Unlock()
+
+	err := m.state.acquire(ctx, func() bool {
+		if m.writer || m.readers > 0 {
+			return false
+		}
+		m.writer = true
+		m.waitingWriters--
+		return true
+	})
+
+	if err != nil {
+		m.state.mu.Lock()
+		m.waitingWriters--
+		//readers blocked by this writer can go now
+		m.state.notify()
+		m.state.mu.Unlock()
+	}
+	return err
+}
+
+//Unlock unlocks m for writing. it panics if m is not locked for writing
+func (m *CtxRWMutex) Unlock() {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	if !m.writer {
+		panic("parallel: unlock of unlocked CtxRWMutex")
+	}
+	m.writer = false
+	m.state.notify()
+}
+
+//RLock locks m for reading. if ctx is done first, it returns ctx.Err() and m is not locked
+func (m *CtxRWMutex) RLock(ctx context.Context) error {
+	return m.state.acquire(ctx, func() bool {
+		if m.writer || m.waitingWriters > 0 {
+			return false
+		}
+		m.readers++
+		return true
+	})
+}
+
+//RUnlock undoes a single RLock. it panics if m is not locked for reading
+func (m *CtxRWMutex) RUnlock() {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	if m.readers <= 0 {
+		panic("parallel: runlock of unlocked CtxRWMutex")
+	}
+	m.readers--
+	if m.readers == 0 {
+		m.state.notify()
+	}
+}
+
+//KeyedMutex is a set of [CtxMutex] by key, so callbacks that change per-key state
+//such as files or rows serialize only on the same key and stay parallel across keys.
+//the lock of a key is removed when no one holds or waits for it.
+//the zero value is ready to use
+//
+// var locks parallel.KeyedMutex[string]
+// parallel.ForContext(ctx, 0, len(events), func(ctx context.Context, i int) {
+// 		if err := locks.Lock(ctx, events[i].UserID); err != nil {
+// 			return
+// 		}
+// 		defer locks.Unlock(events[i].UserID)
+// 		apply(events[i])
+// })
+type KeyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+//keyedLock is the lock of a key of KeyedMutex
+type keyedLock struct {
+	m CtxMutex
+
+	//refs is the number of holders and waiters
+	refs int
+}
+
+//Lock locks k. if ctx is done first, it returns ctx.Err() and k is not locked
+func (m *KeyedMutex[K]) Lock(ctx context.Context, k K) error {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[K]*keyedLock)
+	}
+	l, ok := m.locks[k]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[k] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	if err := l.m.Lock(ctx); err != nil {
+		m.mu.Lock()
+		m.release(k, l)
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+//Unlock unlocks k. it panics if k is not locked
+func (m *KeyedMutex[K]) Unlock(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.locks[k]
+	if !ok {
+		panic("parallel: unlock of unlocked KeyedMutex key")
+	}
+	l.m.Unlock()
+	m.release(k, l)
+}
+
+//release drops a reference to the lock of k. m.mu must be held
+func (m *KeyedMutex[K]) release(k K, l *keyedLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, k)
+	}
+}
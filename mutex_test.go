@@ -0,0 +1,114 @@
+package parallel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestCtxMutex(t *testing.T) {
+	var mu parallel.CtxMutex
+	count := 0
+	parallel.For(0, 100, func(i int) {
+		if err := mu.Lock(context.Background()); err != nil {
+			t.Error(err)
+			return
+		}
+		defer mu.Unlock()
+		count++
+	})
+
+	if count != 100 {
+		t.Error("require 100", count)
+	}
+}
+
+func TestCtxMutexContext(t *testing.T) {
+	var mu parallel.CtxMutex
+	if !mu.TryLock() {
+		t.Fatal("require lock")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := mu.Lock(ctx); err != context.DeadlineExceeded {
+		t.Error("require timeout", err)
+	}
+
+	mu.Unlock()
+	if err := mu.Lock(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCtxRWMutex(t *testing.T) {
+	var mu parallel.CtxRWMutex
+	ctx := context.Background()
+	mu.RLock(ctx)
+	mu.RLock(ctx)
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := mu.Lock(timeout); err != context.DeadlineExceeded {
+		t.Error("writer must wait for readers", err)
+	}
+
+	mu.RUnlock()
+	mu.RUnlock()
+	if err := mu.Lock(ctx); err != nil {
+		t.Error(err)
+	}
+
+	timeout2, cancel2 := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel2()
+	if err := mu.RLock(timeout2); err != context.DeadlineExceeded {
+		t.Error("reader must wait for writer", err)
+	}
+	mu.Unlock()
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var mu parallel.KeyedMutex[int]
+	counts := make([]int, 4)
+	parallel.For(0, 100, func(i int) {
+		k := i % 4
+		if err := mu.Lock(context.Background(), k); err != nil {
+			t.Error(err)
+			return
+		}
+		defer mu.Unlock(k)
+		counts[k]++
+	})
+
+	for k, n := range counts {
+		if n != 25 {
+			t.Error("require 25", k, n)
+		}
+	}
+}
+
+func TestKeyedMutexContext(t *testing.T) {
+	var mu parallel.KeyedMutex[string]
+	ctx := context.Background()
+	mu.Lock(ctx, "a")
+
+	//other keys are not blocked
+	if err := mu.Lock(ctx, "b"); err != nil {
+		t.Error(err)
+	}
+	mu.Unlock("b")
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := mu.Lock(timeout, "a"); err != context.DeadlineExceeded {
+		t.Error("require timeout", err)
+	}
+
+	mu.Unlock("a")
+	if err := mu.Lock(ctx, "a"); err != nil {
+		t.Error(err)
+	}
+	mu.Unlock("a")
+}
@@ -0,0 +1,364 @@
+package parallel
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+//Option configures a call of the functions that accept options
+type Option func(*config)
+
+//config is the result of applying options
+type config struct {
+	policy           Policy
+	rand             *lockedRand
+	bytes            bool
+	orderedResults   bool
+	watchdog         *watchdog
+	stall            *stallConfig
+	descending       bool
+	limiter          Limiter
+	queue            QueueImplementation
+	sortedKeys       bool
+	keyOrder         interface{}
+	claiming         bool
+	paddedResults    bool
+	monitor          Monitor
+	collectAllErrors bool
+	maxErrors        int
+	timings          *Timings
+	errorMapper      func(i int, err error) error
+	ignoreErrors     []func(error) bool
+	traceIDs         func(i int) string
+	budget           *budgetGate
+	record           *Schedule
+	replay           *scheduleReplayer
+	cooperative      cooperativeMode
+	keyLess          func(a, b any) bool
+	workers          int
+	postOrder        bool
+	continueOnError  bool
+	startJitter      time.Duration
+	prefetch         *prefetchConfig
+	maxGoroutines    *goroutineAssertion
+	throttled        func(error) bool
+	manifest         *manifestWriter
+	scratch          *scratchPool
+	sequential       bool
+	logger           *slog.Logger
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+//WithPolicy applies p to each task
+func WithPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+//WithRandSource uses src for everything random in the call, such as [WithJitter].
+//the same seed gives the same random numbers, so the call can be reproduced in tests
+//
+// parallel.AllWithOptions(ctx, tasks, parallel.WithRandSource(rand.NewSource(1).(rand.Source64)))
+func WithRandSource(src rand.Source64) Option {
+	return func(c *config) {
+		c.rand = newLockedRand(src)
+	}
+}
+
+//boundPolicy returns the policy of the call bound to the random source of the call
+func (c *config) boundPolicy() Policy {
+	p := c.policy
+	p.Backoff = bindRand(p.Backoff, c.rand)
+	return p
+}
+
+//ErrConflictingOptions is returned by [Options.Validate] when options of a call conflict
+var ErrConflictingOptions = errors.New("parallel: conflicting options")
+
+//Options is the configuration that a call runs with, returned by [EffectiveOptions].
+//the zero value is the configuration of a call without options,
+//except for Cooperative, which depends on the platform.
+//Limiter and Policy.Backoff are interfaces that may hold a func, such as a [BackoffFunc],
+//so comparing two Options with == can panic. compare their String instead
+type Options struct {
+	//Policy is the policy of each task of [WithPolicy]
+	Policy Policy
+
+	//Claiming is true with [WithClaiming] or [WithWorkers]
+	Claiming bool
+
+	//Workers is the number of workers of a claiming or sequential loop.
+	//it is 0 when the loop starts a goroutine for each iteration
+	Workers int
+
+	//Sequential is true with [WithSequential]
+	Sequential bool
+
+	//Limiter is the limiter of [WithLimiter] or [WithRateLimit]
+	Limiter Limiter
+
+	//Budget is the budget of [WithBudget]
+	Budget *Budget
+
+	//CollectAllErrors is true with [WithCollectAllErrors]
+	CollectAllErrors bool
+
+	//MaxErrors is the number of errors retained by [WithCollectAllErrors]. 0 means all errors
+	MaxErrors int
+
+	//IgnoreErrors is the number of matchers of [WithIgnoreErrors]
+	IgnoreErrors int
+
+	//ErrorMapper is true with [WithErrorMapper]
+	ErrorMapper bool
+
+	//ContinueOnError is true with [WithContinueOnError]
+	ContinueOnError bool
+
+	//AdaptiveBackpressure is true with [WithAdaptiveBackpressure]
+	AdaptiveBackpressure bool
+
+	//Descending is true with [WithDescending]
+	Descending bool
+
+	//OrderedResults is true with [WithOrderedResults]
+	OrderedResults bool
+
+	//PaddedResults is true with [WithPaddedResults]
+	PaddedResults bool
+
+	//SortedKeys is true with [WithSortedKeys]
+	SortedKeys bool
+
+	//KeyOrder is true with [WithKeyOrder]
+	KeyOrder bool
+
+	//KeyLess is true with [WithKeyLess]
+	KeyLess bool
+
+	//PostOrder is true with [WithPostOrder]
+	PostOrder bool
+
+	//Bytes is true with [WithBytes]
+	Bytes bool
+
+	//Queue is the queue of [WithQueueImplementation]
+	Queue QueueImplementation
+
+	//Cooperative is whether the loop yields between iterations. see [WithCooperative]
+	Cooperative bool
+
+	//StartJitter is the maximum delay of [WithStartJitter]
+	StartJitter time.Duration
+
+	//Prefetch is the number of indices prefetched ahead by [WithPrefetch]. 0 means no prefetch
+	Prefetch int
+
+	//Watchdog is the expected duration of [WithWatchdog]. 0 means no watchdog
+	Watchdog time.Duration
+
+	//StallTimeout is the duration of [WithStallDetection]. 0 means no stall detection
+	StallTimeout time.Duration
+
+	//StallCancel is true with [WithStallCancel]
+	StallCancel bool
+
+	//RecordSchedule is true with [WithRecordSchedule]
+	RecordSchedule bool
+
+	//ReplaySchedule is true with [WithReplaySchedule]
+	ReplaySchedule bool
+
+	//Monitor is true with [WithMonitor]
+	Monitor bool
+
+	//Manifest is true with [WithManifest]
+	Manifest bool
+
+	//Timings is true with [WithTimings]
+	Timings bool
+
+	//TraceIDs is true with [WithTraceIDs]
+	TraceIDs bool
+
+	//RandSource is true with [WithRandSource]
+	RandSource bool
+
+	//MaxGoroutines is the limit of [WithMaxGoroutineAssertion]. 0 means no assertion
+	MaxGoroutines int
+
+	//Scratch is true with [WithScratch]
+	Scratch bool
+
+	//Logger is true with [WithLogger]
+	Logger bool
+}
+
+//EffectiveOptions returns the configuration that a call with opts runs with.
+//when an option is given more than once, the last one is used
+//
+// o := parallel.EffectiveOptions(opts...)
+// if err := o.Validate(); err != nil {
+// 		return err
+// }
+func EffectiveOptions(opts ...Option) Options {
+	c := newConfig(opts)
+	o := Options{
+		Policy:               c.policy,
+		Claiming:             c.claiming,
+		Sequential:           c.sequential,
+		Limiter:              c.limiter,
+		CollectAllErrors:     c.collectAllErrors,
+		MaxErrors:            c.maxErrors,
+		IgnoreErrors:         len(c.ignoreErrors),
+		ErrorMapper:          c.errorMapper != nil,
+		ContinueOnError:      c.continueOnError,
+		AdaptiveBackpressure: c.throttled != nil,
+		Descending:           c.descending,
+		OrderedResults:       c.orderedResults,
+		PaddedResults:        c.paddedResults,
+		SortedKeys:           c.sortedKeys,
+		KeyOrder:             c.keyOrder != nil,
+		KeyLess:              c.keyLess != nil,
+		PostOrder:            c.postOrder,
+		Bytes:                c.bytes,
+		Queue:                c.queue,
+		Cooperative:          c.cooperative.yields(),
+		StartJitter:          c.startJitter,
+		RecordSchedule:       c.record != nil,
+		ReplaySchedule:       c.replay != nil,
+		Monitor:              c.monitor != nil,
+		Manifest:             c.manifest != nil,
+		Timings:              c.timings != nil,
+		TraceIDs:             c.traceIDs != nil,
+		RandSource:           c.rand != nil,
+		Scratch:              c.scratch != nil,
+		Logger:               c.logger != nil,
+	}
+	if c.claiming || c.sequential {
+		o.Workers = workerCount(c.workerLimit())
+	}
+	if c.budget != nil {
+		o.Budget = c.budget.budget
+	}
+	if c.prefetch != nil {
+		o.Prefetch = c.prefetch.ahead
+	}
+	if c.watchdog != nil {
+		o.Watchdog = c.watchdog.expected
+	}
+	if c.stall != nil {
+		o.StallTimeout = c.stall.timeout
+		o.StallCancel = c.stall.cancel
+	}
+	if c.maxGoroutines != nil {
+		o.MaxGoroutines = c.maxGoroutines.max
+	}
+	return o
+}
+
+//Validate returns an error that wraps [ErrConflictingOptions] for each pair of options that conflict,
+//such as [WithReplaySchedule] that disables [WithWorkers],
+//and an error for each option with an invalid value
+func (o Options) Validate() error {
+	var errs []error
+	conflict := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrConflictingOptions}, args...)...))
+	}
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("parallel: invalid option: "+format, args...))
+	}
+
+	if o.Claiming && o.ReplaySchedule {
+		conflict("WithReplaySchedule disables WithClaiming and WithWorkers")
+	}
+	if o.Sequential && o.ReplaySchedule {
+		conflict("WithReplaySchedule disables WithSequential")
+	}
+	if o.ContinueOnError && o.IgnoreErrors > 0 {
+		conflict("WithContinueOnError and WithIgnoreErrors both go on after errors, use one of them")
+	}
+	if o.KeyOrder && (o.KeyLess || o.SortedKeys) {
+		conflict("WithKeyOrder overrides WithKeyLess and WithSortedKeys")
+	}
+	if o.KeyLess && o.SortedKeys {
+		conflict("WithKeyLess overrides WithSortedKeys")
+	}
+	if o.StallCancel && o.StallTimeout <= 0 {
+		conflict("WithStallCancel needs WithStallDetection")
+	}
+	if o.MaxGoroutines < 0 {
+		invalid("WithMaxGoroutineAssertion %d", o.MaxGoroutines)
+	}
+	if o.Policy.Retries < 0 {
+		invalid("Policy.Retries %d", o.Policy.Retries)
+	}
+	if o.Policy.Timeout < 0 {
+		invalid("Policy.Timeout %v", o.Policy.Timeout)
+	}
+	if o.StartJitter < 0 {
+		invalid("WithStartJitter %v", o.StartJitter)
+	}
+	return errors.Join(errs...)
+}
+
+//String returns the options that are not the zero value,
+//such as parallel.Options{Claiming: true, Workers: 8}
+func (o Options) String() string {
+	var fields []string
+	add := func(name string, v any, set bool) {
+		if set {
+			fields = append(fields, fmt.Sprintf("%s: %v", name, v))
+		}
+	}
+	add("Policy", fmt.Sprintf("%+v", o.Policy), o.Policy != Policy{})
+	add("Claiming", o.Claiming, o.Claiming)
+	add("Workers", o.Workers, o.Workers != 0)
+	add("Sequential", o.Sequential, o.Sequential)
+	add("Limiter", fmt.Sprintf("%T", o.Limiter), o.Limiter != nil)
+	add("Budget", true, o.Budget != nil)
+	add("CollectAllErrors", o.CollectAllErrors, o.CollectAllErrors)
+	add("MaxErrors", o.MaxErrors, o.MaxErrors != 0)
+	add("IgnoreErrors", o.IgnoreErrors, o.IgnoreErrors != 0)
+	add("ErrorMapper", o.ErrorMapper, o.ErrorMapper)
+	add("ContinueOnError", o.ContinueOnError, o.ContinueOnError)
+	add("AdaptiveBackpressure", o.AdaptiveBackpressure, o.AdaptiveBackpressure)
+	add("Descending", o.Descending, o.Descending)
+	add("OrderedResults", o.OrderedResults, o.OrderedResults)
+	add("PaddedResults", o.PaddedResults, o.PaddedResults)
+	add("SortedKeys", o.SortedKeys, o.SortedKeys)
+	add("KeyOrder", o.KeyOrder, o.KeyOrder)
+	add("KeyLess", o.KeyLess, o.KeyLess)
+	add("PostOrder", o.PostOrder, o.PostOrder)
+	add("Bytes", o.Bytes, o.Bytes)
+	add("Queue", o.Queue, o.Queue != QueueChannel)
+	add("Cooperative", o.Cooperative, o.Cooperative)
+	add("StartJitter", o.StartJitter, o.StartJitter != 0)
+	add("Prefetch", o.Prefetch, o.Prefetch != 0)
+	add("Watchdog", o.Watchdog, o.Watchdog != 0)
+	add("StallTimeout", o.StallTimeout, o.StallTimeout != 0)
+	add("StallCancel", o.StallCancel, o.StallCancel)
+	add("RecordSchedule", o.RecordSchedule, o.RecordSchedule)
+	add("ReplaySchedule", o.ReplaySchedule, o.ReplaySchedule)
+	add("Monitor", o.Monitor, o.Monitor)
+	add("Manifest", o.Manifest, o.Manifest)
+	add("Timings", o.Timings, o.Timings)
+	add("TraceIDs", o.TraceIDs, o.TraceIDs)
+	add("RandSource", o.RandSource, o.RandSource)
+	add("MaxGoroutines", o.MaxGoroutines, o.MaxGoroutines != 0)
+	add("Scratch", o.Scratch, o.Scratch)
+	add("Logger", o.Logger, o.Logger)
+	return "parallel.Options{" + strings.Join(fields, ", ") + "}"
+}
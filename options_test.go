@@ -0,0 +1,114 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestAllWithOptions(t *testing.T) {
+	var count int32
+	var tasks []parallel.TaskFunc
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, func() {
+			if atomic.AddInt32(&count, 1)%2 == 0 {
+				panic("fail")
+			}
+		})
+	}
+
+	src := rand.NewSource(1).(rand.Source64)
+	err := parallel.AllWithOptions(context.Background(), tasks,
+		parallel.WithPolicy(parallel.Policy{
+			Retries: 10,
+			Backoff: parallel.WithJitter(parallel.Constant(10*time.Millisecond), 1),
+		}),
+		parallel.WithRandSource(src))
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaceWithOptions(t *testing.T) {
+	err := parallel.RaceWithOptions(context.Background(), []parallel.TaskFunc{
+		func() {
+			panic("fail")
+		},
+		func() {},
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEffectiveOptions(t *testing.T) {
+	o := parallel.EffectiveOptions()
+	o.Cooperative = false
+	if o != (parallel.Options{}) {
+		t.Error(o)
+	}
+	if err := o.Validate(); err != nil {
+		t.Error(err)
+	}
+	if s := o.String(); s != "parallel.Options{}" {
+		t.Error(s)
+	}
+
+	o = parallel.EffectiveOptions(
+		parallel.WithWorkers(8),
+		parallel.WithCollectAllErrors(10),
+		parallel.WithIgnoreErrors(parallel.MatchError(context.Canceled)),
+		parallel.WithQueueImplementation(parallel.QueueRing),
+		parallel.WithCooperative(false),
+		parallel.WithPrefetch(0, func(i int) {}),
+	)
+	if !o.Claiming || o.Workers != 8 || !o.CollectAllErrors || o.MaxErrors != 10 || o.IgnoreErrors != 1 || o.Prefetch != 1 {
+		t.Error(o)
+	}
+	want := "parallel.Options{Claiming: true, Workers: 8, CollectAllErrors: true, MaxErrors: 10, IgnoreErrors: 1, Queue: QueueRing, Prefetch: 1}"
+	if s := o.String(); s != want {
+		t.Error(s)
+	}
+	if err := o.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if o := parallel.EffectiveOptions(parallel.WithWorkers(0)); o.Workers <= 0 {
+		t.Error(o)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	err := parallel.EffectiveOptions(
+		parallel.WithWorkers(4),
+		parallel.WithReplaySchedule(&parallel.Schedule{}),
+		parallel.WithStallCancel(),
+	).Validate()
+	if !errors.Is(err, parallel.ErrConflictingOptions) {
+		t.Fatal(err)
+	}
+	if s := err.Error(); !strings.Contains(s, "WithReplaySchedule") || !strings.Contains(s, "WithStallCancel") {
+		t.Error(s)
+	}
+
+	err = parallel.EffectiveOptions(
+		parallel.WithContinueOnError(),
+		parallel.WithIgnoreErrors(parallel.MatchError(context.Canceled)),
+	).Validate()
+	if !errors.Is(err, parallel.ErrConflictingOptions) || !strings.Contains(err.Error(), "WithIgnoreErrors") {
+		t.Error(err)
+	}
+
+	err = parallel.EffectiveOptions(parallel.WithPolicy(parallel.Policy{Retries: -1})).Validate()
+	if err == nil || errors.Is(err, parallel.ErrConflictingOptions) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,82 @@
+package parallel
+
+import (
+	"context"
+)
+
+//cacheLineSize is the size of a cache line on most machines
+const cacheLineSize = 64
+
+//WithPaddedResults puts each result of [Map] and each accumulator of [Collect]
+//on its own cache line, so workers writing next to each other
+//do not invalidate each other's cache (false sharing).
+//it uses more memory and helps when the work for each element is tiny
+func WithPaddedResults() Option {
+	return func(c *config) {
+		c.paddedResults = true
+	}
+}
+
+//paddedSlot is a value followed by a cache line of padding
+type paddedSlot[T any] struct {
+	value T
+	_     [cacheLineSize]byte
+}
+
+//resultSlots are the places that workers write results to
+type resultSlots[T any] struct {
+	plain  []T
+	padded []paddedSlot[T]
+}
+
+func newResultSlots[T any](n int, padded bool) *resultSlots[T] {
+	if padded {
+		return &resultSlots[T]{padded: make([]paddedSlot[T], n)}
+	}
+	return &resultSlots[T]{plain: make([]T, n)}
+}
+
+func (s *resultSlots[T]) set(i int, v T) {
+	if s.padded != nil {
+		s.padded[i].value = v
+	} else {
+		s.plain[i] = v
+	}
+}
+
+func (s *resultSlots[T]) get(i int) T {
+	if s.padded != nil {
+		return s.padded[i].value
+	}
+	return s.plain[i]
+}
+
+//slice returns the results as a slice
+func (s *resultSlots[T]) slice() []T {
+	if s.padded == nil {
+		return s.plain
+	}
+	results := make([]T, len(s.padded))
+	for i := range s.padded {
+		results[i] = s.padded[i].value
+	}
+	return results
+}
+
+//Map calls f for each element of slice in parallel
+//and returns the results in the order of slice.
+//the loop runs as [WithClaiming]. if f panics, the result is the zero value
+//
+// squares := parallel.Map(s, func(e int) int {
+// 		return e * e
+// })
+func Map[T, R any](slice []T, f func(T) R, opts ...Option) []R {
+	cfg := newConfig(opts)
+	cfg.claiming = true
+
+	results := newResultSlots[R](len(slice), cfg.paddedResults)
+	forRange(context.Background(), len(slice), func(i int) {
+		results.set(i, f(slice[i]))
+	}, cfg)
+	return results.slice()
+}
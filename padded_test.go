@@ -0,0 +1,50 @@
+package parallel_test
+
+import (
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestMap(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	for _, opts := range [][]parallel.Option{nil, {parallel.WithPaddedResults()}} {
+		squares := parallel.Map(s, func(e int) int {
+			return e * e
+		}, opts...)
+
+		for i, e := range s {
+			if squares[i] != e*e {
+				t.Error("require", e*e, squares[i])
+			}
+		}
+	}
+}
+
+func TestCollectPadded(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	if sum := parallel.Collect(s, parallel.SumCollector[int](), parallel.WithPaddedResults()); sum != 499500 {
+		t.Error("require 499500", sum)
+	}
+}
+
+func benchmarkMap(b *testing.B, opts ...parallel.Option) {
+	s := make([]int64, 1<<16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parallel.Map(s, func(e int64) int64 {
+			return e + 1
+		}, opts...)
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	benchmarkMap(b)
+}
+
+func BenchmarkMapPadded(b *testing.B) {
+	benchmarkMap(b, parallel.WithPaddedResults())
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"reflect"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-var emptyIn = []reflect.Value{}
 var emptyContext = context.Background()
 
 //ForLoop type is used in the For function
@@ -31,260 +32,252 @@ func For(begin int, end int, f ForLoop) {
 //ForWithContext function repeats in parallel, starting with begin and ending with end.
 //Internally, it call the ForLoop function each loop
 func ForWithContext(c context.Context, begin int, end int, f ForLoop) {
-	length := end - begin
-
-	if length > 0 {
-		ctx, cacnel := context.WithCancel(c)
-		go doLoop(cacnel, begin, end, f)
-		<-ctx.Done()
-	}
+	forWithConfig(c, begin, end, f, &config{})
 }
 
-//doLoop calls the function received as argument in [For]
-func doLoop(ctxCancel context.CancelFunc, begin int, end int, f ForLoop) {
-
-	wg := sync.WaitGroup{}
-	wg.Add(end - begin)
+//ForWithOptions is [ForWithContext] configured by opts
+func ForWithOptions(c context.Context, begin int, end int, f ForLoop, opts ...Option) {
+	forWithConfig(c, begin, end, f, newConfig(opts))
+}
 
-	for i := begin; i < end; i++ {
-		go func(it int) {
-			defer wg.Done()
-			defer defaultRecover()
+func forWithConfig(c context.Context, begin int, end int, f ForLoop, cfg *config) {
+	forErrWithConfig(c, begin, end, func(i int) error {
+		f(i)
+		return nil
+	}, cfg)
+}
 
-			//function call
-			f(it)
-		}(i)
-	}
+//errLoop is a ForLoop that returns the error of the iteration
+type errLoop func(i int) error
 
-	wg.Wait()
-	ctxCancel()
-}
+//workerLoop is an errLoop that also receives the worker of the iteration.
+//worker is -1 if the iteration runs in its own goroutine
+type workerLoop func(worker int, i int) error
 
-//ForEachSlice loops the slice in parallel
-//If put multiple options, only the first one is valid.
-//slice: slice, array
-//f: any function
-//
-// s := []int{1,2,3,4,5}
-// parallel.ForEachSlice(s, func(i int, e int) {
-// 		fmt.Println(i, e)
-// })
-func ForEachSlice(slice interface{}, f interface{}) {
-	ForEachSliceWithContext(emptyContext, slice, f)
+//forRange calls f for [0, n) configured by cfg.
+//with WithDescending, it counts down from n - 1
+func forRange(c context.Context, n int, f ForLoop, cfg *config) {
+	forErrRange(c, n, func(i int) error {
+		f(i)
+		return nil
+	}, cfg)
 }
 
-//ForEachSliceWithContext loops the slice in parallel
-//If put multiple options, only the first one is valid.
-//slice: slice, array
-//f: any function
-//
-// s := []int{1,2,3,4,5}
-// parallel.ForEachSlice(s, func(i int, e int) {
-// 		fmt.Println(i, e)
-// })
-func ForEachSliceWithContext(ctx context.Context, slice interface{}, f interface{}) {
-	reflectionSlice := reflect.ValueOf(slice)
-	reflectionFunc := reflect.ValueOf(f)
-
-	if reflectionSlice.Len() == 0 {
-		return
+//forErrRange is forRange of errLoop
+func forErrRange(c context.Context, n int, f errLoop, cfg *config) {
+	if cfg.descending {
+		forErrWithConfig(c, n-1, -1, f, cfg)
+	} else {
+		forErrWithConfig(c, 0, n, f, cfg)
 	}
+}
 
-	funcType := reflect.TypeOf(f)
-	funcArgc := funcType.NumIn()
-
-	sliceType := reflect.TypeOf(slice)
-
-	if funcArgc == 2 {
-		/**
-		* for i, e := range slice {
-		*	f(i, e)
-		* }
-		**/
+func forErrWithConfig(c context.Context, begin int, end int, f errLoop, cfg *config) {
+	c = withRunID(c)
+	runLoop(c, newLoop(RunID(c), begin, end, cfg), func(worker int, i int) error {
+		return f(i)
+	}, cfg)
+}
 
-		if !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
-			//reflect.TypeOf(0) = int type
-			panic("first argument is not an int")
+//runLoop calls f for each index of l.
+//c must carry the run id of l
+func runLoop(c context.Context, l *loop, f workerLoop, cfg *config) {
+	if l.len() > 0 {
+		if cfg.watchdog != nil {
+			defer cfg.watchdog.watch(l)()
 		}
-
-		if elemType, argType := sliceType.Elem(), funcType.In(1); !elemType.AssignableTo(argType) {
-			panic(fmt.Sprintf("slice value type: %v but func second arg type: %v", elemType, argType))
+		if l.monitor != nil {
+			l.monitor.OnStart(l.len())
 		}
 
-		ForWithContext(ctx, 0, reflectionSlice.Len(), func(i int) {
-			reflectionFunc.Call([]reflect.Value{reflect.ValueOf(i), reflectionSlice.Index(i)})
-		})
-	} else if funcArgc == 1 {
-		/**
-		* for i := range slice {
-		*	f(i)
-		* }
-		**/
-
-		if !reflect.TypeOf(0).AssignableTo(funcType.In(0)) {
-			//reflect.TypeOf(0) = int type
-			panic("first argument is not an int")
+		ctx, cacnel := context.WithCancel(c)
+		go doLoop(ctx, cacnel, l, f)
+		<-ctx.Done()
+		if l.leaks != nil {
+			l.leaks.returned()
 		}
-
-		ForWithContext(ctx, 0, reflectionSlice.Len(), func(i int) {
-			reflectionFunc.Call([]reflect.Value{reflect.ValueOf(i)})
-		})
-	} else if funcArgc == 0 {
-		/**
-		* for _ := range slice {
-		*	f()
-		* }
-		**/
-		ForWithContext(ctx, 0, reflectionSlice.Len(), func(_ int) {
-			reflectionFunc.Call(emptyIn)
-		})
 	}
 }
 
-//ForEachMap loops the Map in parallel
-//If put multiple options, only the first one is valid.
-//m: map
-//f: any function
-// a := map[string]int{
-// 	"a": 1,
-// 	"b": 2,
-// 	"c": 3,
-// 	"d": 4,
-// 	"e": 5,
-// }
-// parallel.ForEachMap(a, func(k string, v int) {
-// 		fmt.Println(k, v)
-// })
-func ForEachMap(m interface{}, f interface{}) {
-	ForEachMapWithContext(emptyContext, m, f)
+//loop is the state of a call of [For]
+type loop struct {
+	runID    string
+	begin    int
+	end      int
+	step     int
+	limiter  Limiter
+	budget   *budgetGate
+	claiming bool
+	workers  int
+	monitor  Monitor
+	record   *Schedule
+	replay   *scheduleReplayer
+	yield    bool
+	tracking bool
+	leaks    *leakTracker
+	jitter   time.Duration
+	rand     *lockedRand
+	prefetch *prefetcher
+	running  *goroutineCounter
+	throttle *backpressure
+	start    time.Time
+	started  int64
+	finished int64
+	failed   int64
 }
 
-//ForEachMapWithContext loops the Map in parallel
-//If put multiple options, only the first one is valid.
-//m: map
-//f: any function
-// a := map[string]int{
-// 	"a": 1,
-// 	"b": 2,
-// 	"c": 3,
-// 	"d": 4,
-// 	"e": 5,
-// }
-// parallel.ForEachMap(a, func(k string, v int) {
-// 		fmt.Println(k, v)
-// })
-func ForEachMapWithContext(ctx context.Context, m interface{}, f interface{}) {
-	reflectionMap := reflect.ValueOf(m)
-
-	if reflectionMap.Len() == 0 {
-		return
+func newLoop(runID string, begin int, end int, cfg *config) *loop {
+	step := 1
+	if cfg.descending {
+		step = -1
+	}
+	l := &loop{
+		runID:   runID,
+		begin:   begin,
+		end:     end,
+		step:    step,
+		limiter: cfg.limiter,
+		budget:  cfg.budget,
+		//workers that claim in index order would wait forever for a later index of the schedule
+		claiming: (cfg.claiming || cfg.sequential) && cfg.replay == nil,
+		workers:  cfg.workerLimit(),
+		monitor:  cfg.loopMonitor(runID),
+		record:   cfg.record,
+		replay:   cfg.replay,
+		yield:    cfg.cooperative.yields(),
+		leaks:    newLeakTracker("iteration"),
+		//the watchdog reports the running iterations
+		tracking: dumpActive.Load() || cfg.watchdog != nil,
+		jitter:   cfg.startJitter,
+		rand:     cfg.rand,
+		prefetch: newPrefetcher(cfg.prefetch),
+		running:  newGoroutineCounter(cfg.maxGoroutines),
+		start:    time.Now(),
+	}
+	if cfg.throttled != nil {
+		max := l.len()
+		if l.claiming {
+			max = workerCount(l.workers)
+		}
+		l.throttle = newBackpressure(cfg.throttled, max)
 	}
+	return l
+}
 
-	reflectionFunc := reflect.ValueOf(f)
-
-	funcType := reflect.TypeOf(f)
-	funcArgc := funcType.NumIn()
-
-	mapType := reflectionMap.Type()
-	mapKeys := reflectionMap.MapKeys()
-	if funcArgc == 2 {
-		/**
-		* for k, v := range m {
-		*	f(k, v)
-		* }
-		**/
-		if keyType, argType := mapType.Key(), funcType.In(0); !keyType.AssignableTo(argType) {
-			panic(fmt.Sprintf("map keyType: %v but func first argType: %v", keyType, argType))
+//len returns the number of iterations.
+//if the range is reversed, it is 0
+func (l *loop) len() int {
+	if n := (l.end - l.begin) * l.step; n > 0 {
+		return n
+	}
+	return 0
+}
+
+//run waits until the iteration it may start and calls it in the goroutine of id.
+//it returns false if ctx is done before the iteration starts
+func (l *loop) run(ctx context.Context, id uint64, worker int, f workerLoop, it int) bool {
+	if l.jitter > 0 && !l.waitJitter(ctx) {
+		return false
+	}
+	if l.prefetch != nil && !l.prefetch.wait(ctx, l, it) {
+		return false
+	}
+	if l.throttle != nil {
+		if !l.throttle.acquire(ctx) {
+			return false
 		}
-		if valType, argType := mapType.Elem(), funcType.In(1); !valType.AssignableTo(argType) {
-			panic(fmt.Sprintf("map valueType: %v but func second argType: %v", valType, argType))
+		l.throttle.release(l.call(id, worker, f, it))
+		return true
+	}
+
+	l.call(id, worker, f, it)
+	return true
+}
+
+//call calls f for the iteration it in the goroutine of id
+//and returns the error of the iteration
+func (l *loop) call(id uint64, worker int, f workerLoop, it int) (err error) {
+	if l.monitor != nil {
+		start := time.Now()
+		defer func() {
+			l.monitor.OnItemDone(it, time.Since(start), err)
+		}()
+	}
+	defer func() {
+		if err != nil {
+			atomic.AddInt64(&l.failed, 1)
 		}
-		ForWithContext(ctx, 0, len(mapKeys), func(i int) {
-			key := mapKeys[i]
-			reflectionFunc.Call([]reflect.Value{key, reflectionMap.MapIndex(key)})
-		})
-	} else if funcArgc == 1 {
-		/**
-		* for k := range m {
-		*	f(k)
-		* }
-		**/
-		if keyType, argType := mapType.Key(), funcType.In(0); !keyType.AssignableTo(argType) {
-			panic(fmt.Sprintf("map key: %v but function first arg: %v", keyType, argType))
+	}()
+	if l.record != nil || l.replay != nil {
+		l.scheduleEvent(ScheduleStart, it)
+		defer l.scheduleEvent(ScheduleDone, it)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintln(os.Stderr, r)
+			err = newPanicError(r)
 		}
-		ForWithContext(ctx, 0, len(mapKeys), func(i int) {
-			reflectionFunc.Call([]reflect.Value{mapKeys[i]})
-		})
-	} else if funcArgc == 0 {
-		/**
-		* for _ := range m {
-		*	f()
-		* }
-		**/
-		ForWithContext(ctx, 0, len(mapKeys), func(_ int) {
-			reflectionFunc.Call(emptyIn)
-		})
+	}()
+	atomic.AddInt64(&l.started, 1)
+	defer atomic.AddInt64(&l.finished, 1)
+	if l.tracking {
+		defer trackIteration(&activeIterations, id, l, worker, it)()
+	}
+	if l.leaks != nil {
+		defer l.leaks.track(it, id)()
+	}
+	if l.running != nil {
+		defer l.running.enter()()
 	}
 
+	//function call
+	err = f(worker, it)
+	return err
 }
 
-//ForEach loops the collection in parallel
-//collection: slice, array, map
-//If put multiple options, only the first one is valid.
-//f: any function
-//
-// ex1)
-// s := []int{1,2,3,4,5}
-// parallel.ForEach(s, func(i int, e int) {
-// 		fmt.Println(i, e)
-// })
-//
-// ex2)
-// a := map[string]int{
-// 	"a": 1,
-// 	"b": 2,
-// 	"c": 3,
-// 	"d": 4,
-// 	"e": 5,
-// }
-// parallel.ForEach(a, func(k string, v int) {
-// 		fmt.Println(k, v)
-// })
-func ForEach(collection interface{}, f interface{}) {
-	ForEachWithContext(emptyContext, collection, f)
-}
+//doLoop calls the function received as argument in [For]
+func doLoop(ctx context.Context, ctxCancel context.CancelFunc, l *loop, f workerLoop) {
+	defer ctxCancel()
+	if l.monitor != nil {
+		defer func() {
+			l.monitor.OnFinish(l.stats())
+		}()
+	}
+	if l.prefetch != nil {
+		go l.prefetch.run(ctx, l)
+	}
+	if l.claiming {
+		doClaimingLoop(ctx, l, f)
+		return
+	}
+	if l.replay != nil {
+		context.AfterFunc(ctx, l.replay.release)
+	}
+
+	wg := sync.WaitGroup{}
+
+	for i := l.begin; i != l.end; i += l.step {
+		if !l.admit(ctx, i) {
+			//the rest are not started
+			if l.replay != nil {
+				l.replay.release()
+			}
+			break
+		}
 
-//ForEachWithContext loops the collection in parallel
-//collection: slice, array, map
-//If put multiple options, only the first one is valid.
-//f: any function
-//
-// ex1)
-// s := []int{1,2,3,4,5}
-// parallel.ForEach(s, func(i int, e int) {
-// 		fmt.Println(i, e)
-// })
-//
-// ex2)
-// a := map[string]int{
-// 	"a": 1,
-// 	"b": 2,
-// 	"c": 3,
-// 	"d": 4,
-// 	"e": 5,
-// }
-// parallel.ForEach(a, func(k string, v int) {
-// 		fmt.Println(k, v)
-// })
-func ForEachWithContext(ctx context.Context, collection interface{}, f interface{}) {
-	collectionKind := reflect.TypeOf(collection).Kind()
-
-	switch collectionKind {
-	case reflect.Slice, reflect.Array:
-		ForEachSliceWithContext(ctx, collection, f)
-	case reflect.Map:
-		ForEachMapWithContext(ctx, collection, f)
+		wg.Add(1)
+		go func(it int) {
+			defer wg.Done()
+			l.run(ctx, l.goroutineID(), -1, f, it)
+		}(i)
+
+		if l.yield {
+			//let the iteration run before the next one starts
+			runtime.Gosched()
+		}
 	}
+
+	wg.Wait()
 }
 
 //TaskFunc functions that are executed in parallel
@@ -300,19 +293,27 @@ func Race(functions ...TaskFunc) {
 //RaceWithContext functions that are passed as arguments are executed in parallel,
 //and when one of them is finished the function is terminated
 // other functions do not force shutdown.
+//use [RaceWithCancel] to cancel other functions.
 func RaceWithContext(ctx context.Context, functions ...TaskFunc) {
 	if len(functions) > 0 {
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel()
-		for _, e := range functions {
-			go func(f TaskFunc) {
+		leaks := newLeakTracker("Race task")
+		for i, e := range functions {
+			go func(i int, f TaskFunc) {
 				defer cancel()
 				defer defaultRecover()
+				if leaks != nil {
+					defer leaks.track(i, goroutineID())()
+				}
 
 				f()
-			}(e)
+			}(i, e)
 		}
 		<-ctx.Done()
+		if leaks != nil {
+			leaks.returned()
+		}
 	}
 }
 
@@ -0,0 +1,213 @@
+package parallel
+
+import (
+	"context"
+	"time"
+)
+
+//Policy is applied to each task of [AllWithPolicy] and [RaceWithPolicy]
+//a task fails when it panics or does not finish within Timeout.
+type Policy struct {
+	//Timeout of each attempt. 0 means no timeout
+	//a timed out attempt is not forced to shut down.
+	Timeout time.Duration
+
+	//Retries is the number of attempts made after the first attempt failed
+	Retries int
+
+	//Backoff decides the delay between attempts.
+	//nil means retry immediately
+	Backoff Backoff
+}
+
+//AllWithPolicy functions are executed in parallel with policy p,
+//and when all functions are finished or gave up, [AllWithPolicy] ends
+//or cancel context called
+//the returned error holds the last error of each function that gave up.
+func AllWithPolicy(ctx context.Context, p Policy, functions ...TaskFunc) error {
+	return AllWithOptions(ctx, functions, WithPolicy(p))
+}
+
+//AllWithOptions is [AllWithPolicy] configured by opts
+func AllWithOptions(ctx context.Context, functions []TaskFunc, opts ...Option) error {
+	cfg := newConfig(opts)
+	p := cfg.boundPolicy()
+	errs := newErrorCollector("task", cfg)
+	timings := newTimingRecorder(cfg.timings, len(functions))
+	defer timings.fill(cfg.timings, -1)
+	forErrRange(ctx, len(functions), func(i int) error {
+		timings.start(i)
+		err := p.run(ctx, functions[i])
+		timings.end(i)
+		return errs.add(i, nil, err)
+	}, cfg)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+//RaceWithPolicy functions are executed in parallel with policy p,
+//and when one of them is finished successfully the function is terminated
+//other functions do not force shutdown.
+//if all functions gave up, the returned error holds the last error of each function.
+func RaceWithPolicy(ctx context.Context, p Policy, functions ...TaskFunc) error {
+	return RaceWithOptions(ctx, functions, WithPolicy(p))
+}
+
+//RaceWithOptions is [RaceWithPolicy] configured by opts
+func RaceWithOptions(ctx context.Context, functions []TaskFunc, opts ...Option) error {
+	cfg := newConfig(opts)
+	p := cfg.boundPolicy()
+	if len(functions) == 0 {
+		return nil
+	}
+
+	winner := -1
+	timings := newTimingRecorder(cfg.timings, len(functions))
+	defer func() {
+		timings.fill(cfg.timings, winner)
+	}()
+
+	if cfg.sequential {
+		var err error
+		winner, err = raceSequential(ctx, functions, p, cfg, timings)
+		return err
+	}
+
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index int
+		err   error
+	}
+	results := make(chan result, len(functions))
+	leaks := newLeakTracker("Race task")
+	if leaks != nil {
+		defer leaks.returned()
+	}
+	for i, e := range functions {
+		go func(i int, f TaskFunc) {
+			if leaks != nil {
+				defer leaks.track(i, goroutineID())()
+			}
+			timings.start(i)
+			err := p.run(raceCtx, f)
+			timings.end(i)
+			results <- result{i, err}
+		}(i, e)
+	}
+
+	errs := newErrorCollector("task", cfg)
+	for range functions {
+		r := <-results
+		if r.err == nil || cfg.ignored(r.err) {
+			winner = r.index
+			return nil
+		}
+		errs.add(r.index, nil, r.err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+//raceSequential calls functions in order until one of them succeeds
+//and returns the index of the winner, or -1
+func raceSequential(ctx context.Context, functions []TaskFunc, p Policy, cfg *config, timings *timingRecorder) (int, error) {
+	errs := newErrorCollector("task", cfg)
+	for i, f := range functions {
+		if ctx.Err() != nil {
+			break
+		}
+		timings.start(i)
+		err := p.run(ctx, f)
+		timings.end(i)
+		if err == nil || cfg.ignored(err) {
+			return i, nil
+		}
+		errs.add(i, nil, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+	return -1, errs.err()
+}
+
+//run calls f until it succeeds or p gives up.
+func (p Policy) run(ctx context.Context, f TaskFunc) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := p.attempt(ctx, f)
+		if err == nil || ctx.Err() != nil || attempt > p.Retries {
+			return err
+		}
+
+		var delay time.Duration
+		if p.Backoff != nil {
+			d, ok := p.Backoff.Next(attempt, time.Since(start))
+			if !ok {
+				return err
+			}
+			delay = d
+		}
+
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+//attempt calls f once and waits for it within p.Timeout
+func (p Policy) attempt(ctx context.Context, f TaskFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+			done <- err
+		}()
+
+		f()
+	}()
+
+	var timeout <-chan time.Time
+	if p.Timeout > 0 {
+		timer := time.NewTimer(p.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-timeout:
+		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//sleepWithContext waits d or until ctx is done
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,118 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestAllWithPolicyTimeout(t *testing.T) {
+	p := parallel.Policy{Timeout: 100 * time.Millisecond}
+	begin := time.Now()
+	err := parallel.AllWithPolicy(context.Background(), p, func() {
+	}, func() {
+		time.Sleep(2 * time.Second)
+	})
+
+	if !errors.Is(err, parallel.ErrTimeout) {
+		t.Error("require timeout error", err)
+	}
+	if time.Since(begin) > time.Second {
+		t.Error("hung task must not block")
+	}
+}
+
+func TestAllWithPolicyRetry(t *testing.T) {
+	var count int32
+	p := parallel.Policy{
+		Retries: 3,
+		Backoff: parallel.Constant(10 * time.Millisecond),
+	}
+	err := parallel.AllWithPolicy(context.Background(), p, func() {
+		if atomic.AddInt32(&count, 1) < 3 {
+			panic("transient")
+		}
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 3 {
+		t.Error("require 3 attempts", count)
+	}
+}
+
+func TestAllWithPolicyGiveUp(t *testing.T) {
+	var count int32
+	p := parallel.Policy{Retries: 2}
+	err := parallel.AllWithPolicy(context.Background(), p, func() {
+		atomic.AddInt32(&count, 1)
+		panic("fail")
+	})
+
+	var panicErr *parallel.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Error("require panic error", err)
+	}
+	if count != 3 {
+		t.Error("require 3 attempts", count)
+	}
+}
+
+func TestAllWithPolicyContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := parallel.AllWithPolicy(ctx, parallel.Policy{}, func() {
+		time.Sleep(2 * time.Second)
+	})
+
+	if err != context.DeadlineExceeded {
+		t.Error("require timeout error", err)
+	}
+}
+
+func TestAllWithOptionsRunID(t *testing.T) {
+	ctx := parallel.ContextWithRunID(context.Background(), "all")
+	m := &recordMonitor{}
+	err := parallel.AllWithOptions(ctx, []parallel.TaskFunc{func() {}, func() {}}, parallel.WithMonitor(m))
+
+	if err != nil {
+		t.Error(err)
+	}
+	if m.finish == nil || m.finish.RunID != "all" {
+		t.Error("require the run id of ctx", m.finish)
+	}
+}
+
+func TestRaceWithPolicy(t *testing.T) {
+	p := parallel.Policy{Timeout: 100 * time.Millisecond, Retries: 1}
+	err := parallel.RaceWithPolicy(context.Background(), p, func() {
+		panic("fail")
+	}, func() {
+		time.Sleep(2 * time.Second)
+	}, func() {
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaceWithPolicyAllFail(t *testing.T) {
+	p := parallel.Policy{Timeout: 50 * time.Millisecond}
+	err := parallel.RaceWithPolicy(context.Background(), p, func() {
+		panic("fail")
+	}, func() {
+		time.Sleep(2 * time.Second)
+	})
+
+	if !errors.Is(err, parallel.ErrTimeout) {
+		t.Error("require timeout error", err)
+	}
+}
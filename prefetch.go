@@ -0,0 +1,89 @@
+package parallel
+
+import (
+	"context"
+)
+
+//prefetchConfig is the configuration of WithPrefetch
+type prefetchConfig struct {
+	ahead int
+	f     func(i int)
+}
+
+//WithPrefetch calls prefetch for each index before the iteration of the index runs,
+//for up to k indices ahead of the iterations that started,
+//so IO such as page reads or cache warms overlaps with the work of the earlier iterations.
+//prefetch is called in the order of the loop from a single goroutine,
+//and an iteration waits until prefetch of its index returned.
+//it is most useful with [WithWorkers] or [WithClaiming].
+//if k < 1, 1 is used
+//
+// parallel.ForWithOptions(ctx, 0, len(pages), process, parallel.WithWorkers(4),
+// 		parallel.WithPrefetch(8, func(i int) {
+// 			cache.Warm(pages[i])
+// 		}))
+func WithPrefetch(k int, prefetch func(i int)) Option {
+	if k < 1 {
+		k = 1
+	}
+	return func(c *config) {
+		c.prefetch = &prefetchConfig{ahead: k, f: prefetch}
+	}
+}
+
+//prefetcher calls the prefetch function of a loop ahead of its iterations
+type prefetcher struct {
+	cfg   *prefetchConfig
+	state waitState
+
+	//started is the number of positions in the loop that iterations reached
+	started int
+
+	//fetched is the number of positions whose prefetch returned
+	fetched int
+}
+
+func newPrefetcher(cfg *prefetchConfig) *prefetcher {
+	if cfg == nil {
+		return nil
+	}
+	return &prefetcher{cfg: cfg}
+}
+
+//run calls the prefetch function for each index of l in order
+func (p *prefetcher) run(ctx context.Context, l *loop) {
+	for pos := 0; pos < l.len(); pos++ {
+		err := p.state.acquire(ctx, func() bool {
+			return pos < p.started+p.cfg.ahead
+		})
+		if err != nil {
+			return
+		}
+
+		callRecover(func() {
+			p.cfg.f(l.begin + pos*l.step)
+		})
+
+		p.state.mu.Lock()
+		p.fetched = pos + 1
+		p.state.notify()
+		p.state.mu.Unlock()
+	}
+}
+
+//wait waits until the prefetch of the index it of l returned.
+//it returns false if ctx is done first
+func (p *prefetcher) wait(ctx context.Context, l *loop, it int) bool {
+	pos := (it - l.begin) * l.step
+
+	p.state.mu.Lock()
+	if pos+1 > p.started {
+		p.started = pos + 1
+		p.state.notify()
+	}
+	p.state.mu.Unlock()
+
+	return p.state.acquire(ctx, func() bool {
+		return p.fetched > pos
+	}) == nil
+}
@@ -0,0 +1,61 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithPrefetch(t *testing.T) {
+	const n = 100
+	var mu sync.Mutex
+	fetched := make([]bool, n)
+	maxAhead := 0
+	processed := 0
+
+	parallel.ForWithOptions(context.Background(), 0, n, func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !fetched[i] {
+			t.Error("require prefetch before the iteration", i)
+		}
+		processed++
+	}, parallel.WithWorkers(2), parallel.WithPrefetch(4, func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetched[i] = true
+		if ahead := i - processed; ahead > maxAhead {
+			maxAhead = ahead
+		}
+	}))
+
+	if processed != n {
+		t.Error("require", n, processed)
+	}
+	//4 ahead of the iterations that started, 2 of which may not have finished
+	if maxAhead > 4+2 {
+		t.Error("prefetched too far ahead", maxAhead)
+	}
+}
+
+func TestWithPrefetchDescending(t *testing.T) {
+	var mu sync.Mutex
+	var order []int
+	parallel.ForWithOptions(context.Background(), 9, -1, func(i int) {
+	}, parallel.WithDescending(), parallel.WithPrefetch(2, func(i int) {
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+	}))
+
+	if len(order) != 10 {
+		t.Fatal("require 10", order)
+	}
+	for k, i := range order {
+		if i != 9-k {
+			t.Fatal("require the order of the loop", order)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+//QueueImplementation selects the queue that feeds values to workers
+type QueueImplementation int
+
+const (
+	//QueueChannel is a buffered channel. it is the default
+	QueueChannel QueueImplementation = iota
+
+	//QueueRing is a ring buffer whose size is a power of two.
+	//it does not allocate after it is created
+	QueueRing
+
+	//QueueLinked is a linked list that allocates a node for each value
+	QueueLinked
+)
+
+func (q QueueImplementation) String() string {
+	switch q {
+	case QueueChannel:
+		return "QueueChannel"
+	case QueueRing:
+		return "QueueRing"
+	case QueueLinked:
+		return "QueueLinked"
+	}
+	return fmt.Sprintf("QueueImplementation(%d)", int(q))
+}
+
+//WithQueueImplementation selects the queue that feeds values to the workers
+//of [ForEachSeqWithContext] and the functions built on it
+func WithQueueImplementation(q QueueImplementation) Option {
+	return func(c *config) {
+		c.queue = q
+	}
+}
+
+//workQueue is a bounded queue of values for workers
+type workQueue[T any] interface {
+	//push adds v. if the queue is full, it waits.
+	//it returns false if ctx is done first
+	push(ctx context.Context, v T) bool
+
+	//pop removes the first value. if the queue is empty, it waits.
+	//it returns false if the queue is closed and empty
+	pop() (T, bool)
+
+	//close tells that no more values are pushed
+	close()
+}
+
+//newWorkQueue creates a queue of q that holds up to capacity values
+func newWorkQueue[T any](q QueueImplementation, capacity int) workQueue[T] {
+	switch q {
+	case QueueRing:
+		return newRingQueue[T](capacity)
+	case QueueLinked:
+		return newLinkedQueue[T](capacity)
+	default:
+		return make(chanQueue[T], capacity)
+	}
+}
+
+type chanQueue[T any] chan T
+
+func (q chanQueue[T]) push(ctx context.Context, v T) bool {
+	select {
+	case q <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (q chanQueue[T]) pop() (T, bool) {
+	v, ok := <-q
+	return v, ok
+}
+
+func (q chanQueue[T]) close() {
+	close(q)
+}
+
+//condQueue is the locking shared by ringQueue and linkedQueue
+type condQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	capacity int
+	count    int
+	closed   bool
+}
+
+func (q *condQueue) init(capacity int) {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	q.capacity = capacity
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+}
+
+//waitNotFull waits until the queue is not full.
+//it returns false if ctx is done first. q.mu must be held
+func (q *condQueue) waitNotFull(ctx context.Context) bool {
+	if q.count < q.capacity {
+		return ctx.Err() == nil
+	}
+
+	//a context that is never done does not need to wake up the waiter
+	if ctx.Done() != nil {
+		stop := context.AfterFunc(ctx, func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			q.notFull.Broadcast()
+		})
+		defer stop()
+	}
+
+	for q.count >= q.capacity && ctx.Err() == nil {
+		q.notFull.Wait()
+	}
+	return ctx.Err() == nil
+}
+
+//waitNotEmpty waits until the queue is not empty.
+//it returns false if the queue is closed and empty. q.mu must be held
+func (q *condQueue) waitNotEmpty() bool {
+	for q.count == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	return q.count > 0
+}
+
+func (q *condQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+}
+
+//ringQueue is a condQueue stored in a ring buffer
+type ringQueue[T any] struct {
+	condQueue
+	buf  []T
+	mask int
+	head int
+}
+
+func newRingQueue[T any](capacity int) *ringQueue[T] {
+	q := &ringQueue[T]{}
+	q.init(capacity)
+
+	size := 1
+	for size < q.capacity {
+		size <<= 1
+	}
+	q.buf = make([]T, size)
+	q.mask = size - 1
+	return q
+}
+
+func (q *ringQueue[T]) push(ctx context.Context, v T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.waitNotFull(ctx) {
+		return false
+	}
+	q.buf[(q.head+q.count)&q.mask] = v
+	q.count++
+	q.notEmpty.Signal()
+	return true
+}
+
+func (q *ringQueue[T]) pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var zero T
+	if !q.waitNotEmpty() {
+		return zero, false
+	}
+	v := q.buf[q.head]
+	q.buf[q.head] = zero
+	q.head = (q.head + 1) & q.mask
+	q.count--
+	q.notFull.Signal()
+	return v, true
+}
+
+type linkedNode[T any] struct {
+	value T
+	next  *linkedNode[T]
+}
+
+//linkedQueue is a condQueue stored in a linked list
+type linkedQueue[T any] struct {
+	condQueue
+	head *linkedNode[T]
+	tail *linkedNode[T]
+}
+
+func newLinkedQueue[T any](capacity int) *linkedQueue[T] {
+	q := &linkedQueue[T]{}
+	q.init(capacity)
+	return q
+}
+
+func (q *linkedQueue[T]) push(ctx context.Context, v T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.waitNotFull(ctx) {
+		return false
+	}
+	n := &linkedNode[T]{value: v}
+	if q.tail == nil {
+		q.head = n
+	} else {
+		q.tail.next = n
+	}
+	q.tail = n
+	q.count++
+	q.notEmpty.Signal()
+	return true
+}
+
+func (q *linkedQueue[T]) pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.waitNotEmpty() {
+		var zero T
+		return zero, false
+	}
+	n := q.head
+	q.head = n.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	q.count--
+	q.notFull.Signal()
+	return n.value, true
+}
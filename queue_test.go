@@ -0,0 +1,105 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+var queueImplementations = map[string]QueueImplementation{
+	"Channel": QueueChannel,
+	"Ring":    QueueRing,
+	"Linked":  QueueLinked,
+}
+
+func TestWorkQueueOrder(t *testing.T) {
+	for name, impl := range queueImplementations {
+		q := newWorkQueue[int](impl, 3)
+		for i := 0; i < 3; i++ {
+			q.push(context.Background(), i)
+		}
+		q.close()
+
+		for i := 0; i < 3; i++ {
+			if v, ok := q.pop(); !ok || v != i {
+				t.Error(name, "require", i, v, ok)
+			}
+		}
+		if _, ok := q.pop(); ok {
+			t.Error(name, "closed queue must be empty")
+		}
+	}
+}
+
+func TestWorkQueueFullContext(t *testing.T) {
+	for name, impl := range queueImplementations {
+		q := newWorkQueue[int](impl, 1)
+		q.push(context.Background(), 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		if q.push(ctx, 2) {
+			t.Error(name, "full queue must wait")
+		}
+		cancel()
+	}
+}
+
+func TestForEachSeqQueueImplementation(t *testing.T) {
+	for name, impl := range queueImplementations {
+		var mu sync.Mutex
+		sum := 0
+		ForEachSeqWithContext(context.Background(), func(yield func(int) bool) {
+			for i := 1; i <= 100; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}, 4, func(v int) {
+			mu.Lock()
+			sum += v
+			mu.Unlock()
+		}, WithQueueImplementation(impl))
+
+		if sum != 5050 {
+			t.Error(name, "require 5050", sum)
+		}
+	}
+}
+
+func benchmarkWorkQueue(b *testing.B, impl QueueImplementation) {
+	const workers = 4
+	q := newWorkQueue[int](impl, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				if _, ok := q.pop(); !ok {
+					return
+				}
+			}
+		}()
+	}
+
+	b.ReportAllocs()
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		q.push(ctx, i)
+	}
+	q.close()
+	wg.Wait()
+}
+
+func BenchmarkWorkQueueChannel(b *testing.B) {
+	benchmarkWorkQueue(b, QueueChannel)
+}
+
+func BenchmarkWorkQueueRing(b *testing.B) {
+	benchmarkWorkQueue(b, QueueRing)
+}
+
+func BenchmarkWorkQueueLinked(b *testing.B) {
+	benchmarkWorkQueue(b, QueueLinked)
+}
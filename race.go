@@ -0,0 +1,41 @@
+package parallel
+
+import (
+	"context"
+)
+
+//ContextTaskFunc functions that are executed in parallel with a context
+type ContextTaskFunc func(ctx context.Context)
+
+//RaceWithCancel functions that are passed as arguments are executed in parallel,
+//and when one of them is finished the function is terminated.
+//unlike [RaceWithContext], the context passed to the functions is canceled
+//when the first function is finished, so other functions can stop their work
+//
+// parallel.RaceWithCancel(ctx, func(ctx context.Context) {
+// 		fetch(ctx, mirror1)
+// }, func(ctx context.Context) {
+// 		fetch(ctx, mirror2)
+// })
+func RaceWithCancel(ctx context.Context, functions ...ContextTaskFunc) {
+	if len(functions) > 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		leaks := newLeakTracker("Race task")
+		for i, e := range functions {
+			go func(i int, f ContextTaskFunc) {
+				defer cancel()
+				defer defaultRecover()
+				if leaks != nil {
+					defer leaks.track(i, goroutineID())()
+				}
+
+				f(ctx)
+			}(i, e)
+		}
+		<-ctx.Done()
+		if leaks != nil {
+			leaks.returned()
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package parallel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestRaceWithCancel(t *testing.T) {
+	canceled := make(chan struct{})
+	parallel.RaceWithCancel(context.Background(), func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+			close(canceled)
+		case <-time.After(2 * time.Second):
+		}
+	}, func(ctx context.Context) {
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("loser must be canceled")
+	}
+}
+
+func TestRaceWithCancelParent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	parallel.RaceWithCancel(ctx, func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	if time.Since(begin) > time.Second {
+		t.Error("require parent cancel")
+	}
+}
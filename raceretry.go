@@ -0,0 +1,88 @@
+package parallel
+
+import (
+	"context"
+	"time"
+)
+
+//RaceRetry runs fs in parallel and returns the value of the first one that succeeds.
+//each participant retries after a failure with the delay of policy,
+//until it succeeds, policy gives up or the race is over.
+//a participant does not wait for a retry that would start after the deadline of ctx.
+//the context passed to the participants is canceled when the race is over.
+//if policy is nil, each participant makes a single attempt.
+//if all participants gave up, the error holds the last error of each participant
+//
+// v, err := parallel.RaceRetry(ctx, parallel.Exponential(100*time.Millisecond, time.Second),
+// 		func(ctx context.Context) (string, error) { return lookup(ctx, dns1) },
+// 		func(ctx context.Context) (string, error) { return lookup(ctx, dns2) },
+// )
+func RaceRetry[T any](ctx context.Context, policy Backoff, fs ...func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if len(fs) == 0 {
+		return zero, nil
+	}
+
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		index int
+		value T
+		err   error
+	}
+	results := make(chan result, len(fs))
+	for i, f := range fs {
+		go func(i int, f func(ctx context.Context) (T, error)) {
+			v, err := retryUntilDeadline(raceCtx, policy, f)
+			results <- result{i, v, err}
+		}(i, f)
+	}
+
+	errs := newErrorCollector("task", &config{})
+	for range fs {
+		r := <-results
+		if r.err == nil {
+			return r.value, nil
+		}
+		errs.add(r.index, nil, r.err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	return zero, errs.err()
+}
+
+//retryUntilDeadline calls f until it succeeds, policy gives up,
+//or the next attempt would start after the deadline of ctx
+func retryUntilDeadline[T any](ctx context.Context, policy Backoff, f func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		v, err := callRetryAttempt(ctx, f)
+		if err == nil || ctx.Err() != nil || policy == nil {
+			return v, err
+		}
+
+		delay, ok := policy.Next(attempt, time.Since(start))
+		if !ok {
+			return v, err
+		}
+		if deadline, has := ctx.Deadline(); has && time.Now().Add(delay).After(deadline) {
+			return v, err
+		}
+		if sleepWithContext(ctx, delay) != nil {
+			return v, err
+		}
+	}
+}
+
+//callRetryAttempt calls f. a panic is returned as *PanicError
+func callRetryAttempt[T any](ctx context.Context, f func(ctx context.Context) (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return f(ctx)
+}
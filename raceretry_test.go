@@ -0,0 +1,55 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestRaceRetry(t *testing.T) {
+	var attempts int32
+	v, err := parallel.RaceRetry(context.Background(), parallel.Constant(time.Millisecond),
+		func(ctx context.Context) (string, error) {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return "", errors.New("flaky")
+			}
+			return "a", nil
+		},
+		func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	)
+
+	if err != nil || v != "a" {
+		t.Error("require a", v, err)
+	}
+	if attempts != 3 {
+		t.Error("require 3 attempts", attempts)
+	}
+}
+
+func TestRaceRetryDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	e := errors.New("down")
+	begin := time.Now()
+	_, err := parallel.RaceRetry(ctx, parallel.Constant(time.Second),
+		func(ctx context.Context) (int, error) {
+			return 0, e
+		},
+	)
+
+	//the retry after a second would miss the deadline, so it gives up at once
+	if elapsed := time.Since(begin); elapsed > 40*time.Millisecond {
+		t.Error("require no wait", elapsed)
+	}
+	if !errors.Is(err, e) {
+		t.Error("require down", err)
+	}
+}
@@ -0,0 +1,35 @@
+package parallel
+
+import (
+	"math/rand"
+	"sync"
+)
+
+//lockedRand is a rand.Rand that is safe for concurrent use.
+//a nil *lockedRand uses the global source of math/rand
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(src rand.Source64) *lockedRand {
+	return &lockedRand{r: rand.New(src)}
+}
+
+func (l *lockedRand) Float64() float64 {
+	if l == nil {
+		return rand.Float64()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	if l == nil {
+		return rand.Int63n(n)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
@@ -0,0 +1,28 @@
+package parallel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestBindRandReproducible(t *testing.T) {
+	b := WithMaxElapsed(WithJitter(Constant(time.Second), 1), time.Hour)
+
+	delays := func() []time.Duration {
+		bound := bindRand(b, newLockedRand(rand.NewSource(42).(rand.Source64)))
+		var d []time.Duration
+		for i := 1; i < 10; i++ {
+			next, _ := bound.Next(i, 0)
+			d = append(d, next)
+		}
+		return d
+	}
+
+	d1, d2 := delays(), delays()
+	for i := range d1 {
+		if d1[i] != d2[i] {
+			t.Error("same seed must give same delays", d1, d2)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//ErrNilFunc is returned by [Reconcile] when create, update or delete is nil
+var ErrNilFunc = errors.New("parallel: nil function")
+
+//reconcileOp is a change applied by [Reconcile]
+type reconcileOp[K comparable, T any] struct {
+	name  string
+	apply func(ctx context.Context, k K, v T) error
+	key   K
+	value T
+}
+
+//Reconcile makes actual look like desired.
+//it calls create for the keys that are only in desired,
+//update for the keys whose values are different and delete for the keys that are only in actual,
+//in parallel. create and update receive the desired value, delete receives the actual value.
+//the errors are aggregated like [ForErr] with the key of each change and the name of the change,
+//and [WithWorkers] bounds the number of changes applied at once.
+//if create, update or delete is nil, it returns ErrNilFunc without applying any change
+//
+// err := parallel.Reconcile(ctx, desired, actual, createUser, updateUser, deleteUser,
+// 		parallel.WithWorkers(8), parallel.WithCollectAllErrors(0))
+func Reconcile[K comparable, T comparable](ctx context.Context, desired, actual map[K]T,
+	create, update, delete func(ctx context.Context, k K, v T) error, opts ...Option) error {
+	equal := func(a, b T) bool {
+		return a == b
+	}
+	return ReconcileFunc(ctx, desired, actual, equal, create, update, delete, opts...)
+}
+
+//ReconcileFunc is [Reconcile] for values that are not comparable.
+//update is called for the keys whose values are not equal
+//
+// err := parallel.ReconcileFunc(ctx, desired, actual, sameUser, createUser, updateUser, deleteUser)
+func ReconcileFunc[K comparable, T any](ctx context.Context, desired, actual map[K]T, equal func(a, b T) bool,
+	create, update, delete func(ctx context.Context, k K, v T) error, opts ...Option) error {
+	switch {
+	case equal == nil:
+		return fmt.Errorf("%w: equal", ErrNilFunc)
+	case create == nil:
+		return fmt.Errorf("%w: create", ErrNilFunc)
+	case update == nil:
+		return fmt.Errorf("%w: update", ErrNilFunc)
+	case delete == nil:
+		return fmt.Errorf("%w: delete", ErrNilFunc)
+	}
+	cfg := newConfig(opts)
+
+	keys := joinKeys(desired, actual)
+	keys = sortKeys(keys, cfg)
+
+	var ops []reconcileOp[K, T]
+	for _, k := range keys {
+		want, inDesired := desired[k]
+		have, inActual := actual[k]
+		switch {
+		case !inActual:
+			ops = append(ops, reconcileOp[K, T]{"create", create, k, want})
+		case !inDesired:
+			ops = append(ops, reconcileOp[K, T]{"delete", delete, k, have})
+		case !equal(want, have):
+			ops = append(ops, reconcileOp[K, T]{"update", update, k, want})
+		}
+	}
+
+	errs := newErrorCollector("change", cfg)
+	forErrRange(ctx, len(ops), func(i int) (err error) {
+		op := ops[i]
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+			if err != nil {
+				//the collector adds the key
+				err = fmt.Errorf("%s: %w", op.name, err)
+			}
+			err = errs.add(i, op.key, err)
+		}()
+
+		return op.apply(ctx, op.key, op.value)
+	}, cfg)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errs.err()
+}
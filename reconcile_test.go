@@ -0,0 +1,96 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestReconcile(t *testing.T) {
+	desired := map[string]int{"a": 1, "b": 2, "c": 3}
+	actual := map[string]int{"b": 2, "c": 30, "d": 4}
+
+	var mu sync.Mutex
+	changes := map[string]string{}
+	record := func(name string) func(ctx context.Context, k string, v int) error {
+		return func(ctx context.Context, k string, v int) error {
+			mu.Lock()
+			changes[k] = name
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	err := parallel.Reconcile(context.Background(), desired, actual,
+		record("create"), record("update"), record("delete"), parallel.WithWorkers(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{"a": "create", "c": "update", "d": "delete"}
+	if len(changes) != len(expect) {
+		t.Error("bad changes", changes)
+	}
+	for k, name := range expect {
+		if changes[k] != name {
+			t.Error("bad change", k, changes[k])
+		}
+	}
+}
+
+func TestReconcileError(t *testing.T) {
+	e := errors.New("denied")
+	ok := func(ctx context.Context, k string, v int) error { return nil }
+	err := parallel.Reconcile(context.Background(), map[string]int{}, map[string]int{"x": 1},
+		ok, ok, func(ctx context.Context, k string, v int) error {
+			return e
+		}, parallel.WithCollectAllErrors(0))
+
+	var errs *parallel.Errors
+	if !errors.As(err, &errs) || len(errs.Items) != 1 {
+		t.Fatal("require 1 error", err)
+	}
+	if item := errs.Items[0]; item.Key != "x" || !errors.Is(item.Err, e) || item.Error() != "key x: delete: denied" {
+		t.Error("bad error", item.Key, item.Err)
+	}
+}
+
+func TestReconcileFunc(t *testing.T) {
+	desired := map[string][]int{"a": {1, 2}, "b": {3}}
+	actual := map[string][]int{"a": {1, 2}, "b": {4}}
+
+	var updated []string
+	nop := func(ctx context.Context, k string, v []int) error { return nil }
+	err := parallel.ReconcileFunc(context.Background(), desired, actual, func(a, b []int) bool {
+		return reflect.DeepEqual(a, b)
+	}, nop, func(ctx context.Context, k string, v []int) error {
+		updated = append(updated, k)
+		return nil
+	}, nop, parallel.WithSequential())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0] != "b" {
+		t.Error("require update b", updated)
+	}
+}
+
+func TestReconcileNilFunc(t *testing.T) {
+	called := false
+	create := func(ctx context.Context, k string, v int) error {
+		called = true
+		return nil
+	}
+	err := parallel.Reconcile(context.Background(), map[string]int{"a": 1}, map[string]int{"b": 2},
+		create, create, nil)
+	if !errors.Is(err, parallel.ErrNilFunc) {
+		t.Error("require ErrNilFunc", err)
+	}
+	if called {
+		t.Error("require no change")
+	}
+}
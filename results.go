@@ -0,0 +1,86 @@
+package parallel
+
+import (
+	"context"
+	"sort"
+)
+
+//Ordered is a list of results in the order of the input.
+//the result at i has Index i
+type Ordered[R any] []Result[R]
+
+//Unordered is a list of results in the order they finished.
+//it does not match the order of the input; use [Unordered.Sort] or [Unordered.ByIndex]
+type Unordered[R any] []Result[R]
+
+//MapOrdered calls f for each element of slice in parallel
+//and returns the results in the order of slice.
+//the result always has len(slice) entries.
+//if ctx is done, the entries that did not finish have the Err of ctx
+//
+// for i, r := range parallel.MapOrdered(ctx, urls, fetch) {
+// 		fmt.Println(urls[i], r.Value, r.Err)
+// }
+func MapOrdered[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) Ordered[R] {
+	o := make(Ordered[R], len(slice))
+	done := make([]bool, len(slice))
+	for _, r := range MapUnordered(ctx, slice, f, opts...) {
+		o[r.Index] = r
+		done[r.Index] = true
+	}
+	for i := range o {
+		if !done[i] {
+			o[i] = Result[R]{Index: i, Err: ctx.Err()}
+		}
+	}
+	return o
+}
+
+//MapUnordered calls f for each element of slice in parallel
+//and returns the results in the order they finished.
+//if ctx is done, the results that did not finish are missing
+func MapUnordered[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) Unordered[R] {
+	results := make(Unordered[R], 0, len(slice))
+	for r := range MapStream(ctx, slice, f, opts...) {
+		results = append(results, r)
+	}
+	return results
+}
+
+//Sort returns the results ordered by Index. u is not changed
+func (u Unordered[R]) Sort() Ordered[R] {
+	o := make(Ordered[R], len(u))
+	copy(o, u)
+	sort.Slice(o, func(i, j int) bool {
+		return o[i].Index < o[j].Index
+	})
+	return o
+}
+
+//ByIndex returns the results by Index
+func (u Unordered[R]) ByIndex() map[int]Result[R] {
+	m := make(map[int]Result[R], len(u))
+	for _, r := range u {
+		m[r.Index] = r
+	}
+	return m
+}
+
+//Values returns the values of the results in the order of the input
+func (o Ordered[R]) Values() []R {
+	values := make([]R, len(o))
+	for i, r := range o {
+		values[i] = r.Value
+	}
+	return values
+}
+
+//Err returns the first error in the order of the input, or nil
+func (o Ordered[R]) Err() error {
+	for _, r := range o {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+	return nil
+}
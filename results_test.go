@@ -0,0 +1,82 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestMapOrdered(t *testing.T) {
+	s := []int{30, 10, 20}
+	results := parallel.MapOrdered(context.Background(), s, func(e int) (int, error) {
+		time.Sleep(time.Duration(e) * time.Millisecond)
+		return e * 2, nil
+	})
+
+	for i, r := range results {
+		if r.Index != i || r.Value != s[i]*2 {
+			t.Error("bad result", i, r)
+		}
+	}
+	if v := results.Values(); v[0] != 60 || v[1] != 20 || v[2] != 40 {
+		t.Error("bad values", v)
+	}
+	if err := results.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMapUnordered(t *testing.T) {
+	e := errors.New("odd")
+	s := []int{30, 1, 10}
+	results := parallel.MapUnordered(context.Background(), s, func(v int) (int, error) {
+		time.Sleep(time.Duration(v) * time.Millisecond)
+		if v%2 == 1 {
+			return 0, e
+		}
+		return v, nil
+	})
+
+	if len(results) != 3 || results[0].Index != 1 {
+		t.Error("require completion order", results)
+	}
+	if r := results.ByIndex()[2]; r.Value != 10 {
+		t.Error("bad ByIndex", r)
+	}
+	if err := results.Sort().Err(); err != e {
+		t.Error("require odd", err)
+	}
+}
+
+func TestMapOrderedCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+
+	s := []int{0, 1, 2, 3}
+	results := parallel.MapOrdered(ctx, s, func(e int) (int, error) {
+		if e == 2 {
+			cancel()
+			<-release
+		}
+		return e, nil
+	}, parallel.WithSequential())
+
+	if len(results) != len(s) {
+		t.Fatal("require len(slice) results", len(results))
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Error("bad index", i, r)
+		}
+		if r.Err != nil && r.Err != context.Canceled {
+			t.Error("bad error", i, r)
+		}
+	}
+	if results[3].Err != context.Canceled {
+		t.Error("require canceled", results[3])
+	}
+}
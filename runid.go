@@ -0,0 +1,53 @@
+package parallel
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+//runIDKey is the context key of the run id.
+//it is not exported, use [RunID] to read the id
+type runIDKey struct{}
+
+//RunID returns the id of the call that ctx was passed by.
+//each loop gets a new random id that is carried by the contexts of its iterations
+//and set in [Stats], [Diagnostics], [Stall] and [ManifestEntry],
+//so the logs, metrics and traces of a single call can be correlated.
+//a call made with a context that already carries an id, such as the context of an iteration,
+//belongs to the same run. if ctx has no run id, it returns ""
+//
+// parallel.ForContext(ctx, 0, len(jobs), func(ctx context.Context, i int) {
+// 		log.Println(parallel.RunID(ctx), "job", i)
+// }, parallel.WithMonitor(monitor))
+func RunID(ctx context.Context) string {
+	id, _ := ctx.Value(runIDKey{}).(string)
+	return id
+}
+
+//ContextWithRunID returns a copy of ctx that carries id.
+//calls made with the returned context use id instead of a new id,
+//for example the id of the job that runs them
+func ContextWithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, id)
+}
+
+//withRunID returns ctx if it carries a run id,
+//or a copy of ctx that carries a new run id
+func withRunID(ctx context.Context) context.Context {
+	if RunID(ctx) != "" {
+		return ctx
+	}
+	return ContextWithRunID(ctx, newRunID())
+}
+
+//newRunID returns a random id of 16 hex digits
+func newRunID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		binary.BigEndian.PutUint64(b[:], uint64(time.Now().UnixNano()))
+	}
+	return hex.EncodeToString(b[:])
+}
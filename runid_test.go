@@ -0,0 +1,65 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestRunID(t *testing.T) {
+	var mu sync.Mutex
+	ids := map[string]int{}
+	m := &recordMonitor{}
+	var manifest bytes.Buffer
+	parallel.ForContext(context.Background(), 0, 10, func(ctx context.Context, i int) {
+		id := parallel.RunID(ctx)
+		parallel.ForContext(ctx, 0, 2, func(ctx context.Context, j int) {
+			if nested := parallel.RunID(ctx); nested != id {
+				t.Error(nested, id)
+			}
+		})
+		mu.Lock()
+		ids[id]++
+		mu.Unlock()
+	}, parallel.WithMonitor(m), parallel.WithManifest(&manifest, nil))
+
+	if len(ids) != 1 {
+		t.Fatal(ids)
+	}
+	for id := range ids {
+		if len(id) != 16 {
+			t.Error(id)
+		}
+		if m.finish == nil || m.finish.RunID != id {
+			t.Error(m.finish)
+		}
+		for _, e := range readManifest(t, &manifest) {
+			if e.RunID != id {
+				t.Error(e)
+			}
+		}
+	}
+
+	var other string
+	parallel.ForContext(context.Background(), 0, 1, func(ctx context.Context, i int) {
+		other = parallel.RunID(ctx)
+	})
+	if _, ok := ids[other]; ok || other == "" {
+		t.Error(other)
+	}
+}
+
+func TestContextWithRunID(t *testing.T) {
+	ctx := parallel.ContextWithRunID(context.Background(), "job-1")
+	parallel.ForContext(ctx, 0, 3, func(ctx context.Context, i int) {
+		if id := parallel.RunID(ctx); id != "job-1" {
+			t.Error(id)
+		}
+	})
+	if id := parallel.RunID(context.Background()); id != "" {
+		t.Error(id)
+	}
+}
@@ -0,0 +1,128 @@
+package parallel
+
+import (
+	"sync"
+)
+
+//ScheduleEventKind is the kind of a [ScheduleEvent]
+type ScheduleEventKind int
+
+const (
+	//ScheduleStart is the start of an iteration
+	ScheduleStart ScheduleEventKind = iota
+
+	//ScheduleDone is the end of an iteration
+	ScheduleDone
+)
+
+//ScheduleEvent is the start or the end of the iteration of Index
+type ScheduleEvent struct {
+	Kind  ScheduleEventKind
+	Index int
+}
+
+//Schedule is the order in which the iterations of a loop started and ended.
+//record it with [WithRecordSchedule] and replay it with [WithReplaySchedule].
+//Events can be saved and loaded, for example as JSON, to replay a schedule seen in production.
+//the zero value is an empty schedule
+type Schedule struct {
+	mu     sync.Mutex
+	Events []ScheduleEvent
+}
+
+//WithRecordSchedule appends the start and the end of each iteration to s
+func WithRecordSchedule(s *Schedule) Option {
+	return func(c *config) {
+		c.record = s
+	}
+}
+
+//WithReplaySchedule starts and ends the iterations in the order of s.
+//an iteration does not start until the events before its start in s have happened,
+//and its end waits for the events before its end.
+//the loop must have the same range and options as the recorded one;
+//indices that are not in s are not ordered.
+//the iterations still run in goroutines, so the replay also works under the race detector.
+//when the loop stops starting iterations, for example because ctx is done or [WithBudget] denied one,
+//the rest of the events are no longer ordered, so no iteration waits for an event that will not happen
+//
+// var s parallel.Schedule
+// json.Unmarshal(recorded, &s)
+// parallel.ForWithOptions(ctx, 0, n, f, parallel.WithReplaySchedule(&s))
+func WithReplaySchedule(s *Schedule) Option {
+	return func(c *config) {
+		c.replay = newScheduleReplayer(s)
+	}
+}
+
+//event appends an event to s
+func (s *Schedule) event(kind ScheduleEventKind, index int) {
+	s.mu.Lock()
+	s.Events = append(s.Events, ScheduleEvent{Kind: kind, Index: index})
+	s.mu.Unlock()
+}
+
+//scheduleReplayer makes the events happen in the order of a Schedule
+type scheduleReplayer struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	events []ScheduleEvent
+	next   int
+	known  map[int]bool
+
+	//released is whether the events are no longer ordered
+	released bool
+}
+
+func newScheduleReplayer(s *Schedule) *scheduleReplayer {
+	s.mu.Lock()
+	events := append([]ScheduleEvent(nil), s.Events...)
+	s.mu.Unlock()
+
+	r := &scheduleReplayer{
+		events: events,
+		known:  make(map[int]bool),
+	}
+	r.cond = sync.NewCond(&r.mu)
+	for _, e := range events {
+		r.known[e.Index] = true
+	}
+	return r
+}
+
+//event waits until the event is the next one of the schedule
+func (r *scheduleReplayer) event(kind ScheduleEventKind, index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.known[index] {
+		return
+	}
+	want := ScheduleEvent{Kind: kind, Index: index}
+	for !r.released && r.next < len(r.events) && r.events[r.next] != want {
+		r.cond.Wait()
+	}
+	if !r.released && r.next < len(r.events) {
+		r.next++
+	}
+	r.cond.Broadcast()
+}
+
+//release stops ordering the events and wakes up the events that wait,
+//because the events they wait for may never happen
+func (r *scheduleReplayer) release() {
+	r.mu.Lock()
+	r.released = true
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+//scheduleEvent passes the event to the schedules of l
+func (l *loop) scheduleEvent(kind ScheduleEventKind, index int) {
+	if l.replay != nil {
+		l.replay.event(kind, index)
+	}
+	if l.record != nil {
+		l.record.event(kind, index)
+	}
+}
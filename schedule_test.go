@@ -0,0 +1,88 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithRecordSchedule(t *testing.T) {
+	var s parallel.Schedule
+	parallel.ForWithOptions(context.Background(), 0, 10, func(i int) {
+	}, parallel.WithRecordSchedule(&s))
+
+	if len(s.Events) != 20 {
+		t.Fatal("require 20 events", len(s.Events))
+	}
+	started := map[int]bool{}
+	for _, e := range s.Events {
+		switch e.Kind {
+		case parallel.ScheduleStart:
+			started[e.Index] = true
+		case parallel.ScheduleDone:
+			if !started[e.Index] {
+				t.Error("done before start", e.Index)
+			}
+		}
+	}
+}
+
+func TestWithReplaySchedule(t *testing.T) {
+	//run one at a time in reverse order
+	s := parallel.Schedule{}
+	for i := 9; i >= 0; i-- {
+		s.Events = append(s.Events,
+			parallel.ScheduleEvent{Kind: parallel.ScheduleStart, Index: i},
+			parallel.ScheduleEvent{Kind: parallel.ScheduleDone, Index: i})
+	}
+
+	for n := 0; n < 3; n++ {
+		var mu sync.Mutex
+		var order []int
+		var recorded parallel.Schedule
+		parallel.ForWithOptions(context.Background(), 0, 10, func(i int) {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		}, parallel.WithReplaySchedule(&s), parallel.WithRecordSchedule(&recorded), parallel.WithClaiming())
+
+		for k, i := range order {
+			if i != 9-k {
+				t.Fatal("bad order", order)
+			}
+		}
+		for k, e := range recorded.Events {
+			if e != s.Events[k] {
+				t.Fatal("bad schedule", recorded.Events)
+			}
+		}
+	}
+}
+
+func TestWithReplayScheduleBudget(t *testing.T) {
+	//iteration 2 starts first in the schedule, but the budget denies it
+	s := &parallel.Schedule{Events: []parallel.ScheduleEvent{
+		{Kind: parallel.ScheduleStart, Index: 2},
+		{Kind: parallel.ScheduleDone, Index: 2},
+		{Kind: parallel.ScheduleStart, Index: 0},
+		{Kind: parallel.ScheduleDone, Index: 0},
+		{Kind: parallel.ScheduleStart, Index: 1},
+		{Kind: parallel.ScheduleDone, Index: 1},
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		parallel.ForWithOptions(context.Background(), 0, 3, func(i int) {
+		}, parallel.WithReplaySchedule(s), parallel.WithBudget(parallel.NewBudget(2, 0, nil), nil))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("require the iterations that wait for iteration 2 to be released")
+	}
+}
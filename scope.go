@@ -0,0 +1,68 @@
+package parallel
+
+import (
+	"context"
+)
+
+//scopeKey is the context key of *Scope.
+//it is not exported, use [ScopeFrom] to read the Scope
+type scopeKey struct{}
+
+//Scope owns the goroutines started in it, so they can be ended together.
+//the goroutines of [Scope.Go], and of [AllAsync] and [RaceAsync] called with the context of a Scope
+//or a context derived from it, belong to the Scope.
+//the loops such as [ForWithContext] do not: they wait for their iterations themselves,
+//except when their context is canceled, in which case the running iterations are not waited for
+//
+// s := parallel.NewScope(ctx)
+// s.Go(func(ctx context.Context) { refresh(ctx) })
+// err := s.Close()
+type Scope struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     WaitGroup
+}
+
+//NewScope creates a Scope whose context is derived from ctx
+func NewScope(ctx context.Context) *Scope {
+	s := &Scope{}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.ctx = context.WithValue(ctx, scopeKey{}, s)
+	return s
+}
+
+//ScopeFrom returns the Scope that ctx belongs to.
+//if ctx does not belong to a Scope, it returns nil
+func ScopeFrom(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+//Context returns the context of the Scope. it is canceled by Close
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+//Go calls f with the context of the Scope in a new goroutine.
+//if f panics, the panic is returned by Close as a *PanicError
+func (s *Scope) Go(f ContextTaskFunc) {
+	s.wg.Go(func() {
+		f(s.ctx)
+	})
+}
+
+//Close cancels the context of the Scope and waits for all goroutines of the Scope to return.
+//it returns the panics of the functions started by Go joined into one error
+func (s *Scope) Close() error {
+	s.cancel()
+	return s.wg.Wait(context.Background())
+}
+
+//goAsync calls f in a new goroutine that belongs to the Scope of ctx, if there is one
+func goAsync(ctx context.Context, f func()) {
+	if s := ScopeFrom(ctx); s != nil {
+		s.wg.Go(f)
+		return
+	}
+	go f()
+}
@@ -0,0 +1,72 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestScope(t *testing.T) {
+	s := parallel.NewScope(context.Background())
+	if parallel.ScopeFrom(s.Context()) != s {
+		t.Error("scope not in context")
+	}
+
+	var stopped int32
+	s.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		atomic.AddInt32(&stopped, 1)
+	})
+	job, cancel := parallel.AllAsync(s.Context(), func(ctx context.Context) {
+		<-ctx.Done()
+		atomic.AddInt32(&stopped, 1)
+	})
+	defer cancel()
+	s.Go(func(ctx context.Context) {
+		panic("fail")
+	})
+
+	var p *parallel.PanicError
+	if err := s.Close(); !errors.As(err, &p) {
+		t.Error(err)
+	}
+	if stopped != 2 {
+		t.Error(stopped)
+	}
+	if err := job.Wait(); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+
+	if s := parallel.ScopeFrom(context.Background()); s != nil {
+		t.Error(s)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var stopped int32
+	h := parallel.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := parallel.ScopeFrom(r.Context())
+		if s == nil {
+			t.Fatal("no scope")
+		}
+		s.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			atomic.AddInt32(&stopped, 1)
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Error(rec.Code)
+	}
+	if atomic.LoadInt32(&stopped) != 1 {
+		t.Error("work of the request outlived the handler")
+	}
+}
@@ -0,0 +1,92 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+//scratchKey is the context key of the scratch slot of an iteration.
+//it is not exported, use [Scratch] to read the value
+type scratchKey struct{}
+
+//WithScratch gives the iterations of [ForContext] a reusable scratch value made by newScratch,
+//such as a *bytes.Buffer or a decoder, that is read with [Scratch].
+//a value is used by a single iteration at a time and is reused by the next iterations,
+//so each worker of [WithWorkers] keeps its own value and at most as many values are made
+//as iterations call [Scratch] at the same time.
+//the value is not reset between iterations
+//
+// parallel.ForContext(ctx, 0, len(docs), func(ctx context.Context, i int) {
+// 		buf := parallel.Scratch(ctx).(*bytes.Buffer)
+// 		buf.Reset()
+// 		render(buf, docs[i])
+// }, parallel.WithWorkers(8), parallel.WithScratch(func() any { return new(bytes.Buffer) }))
+func WithScratch(newScratch func() any) Option {
+	return func(c *config) {
+		c.scratch = &scratchPool{new: newScratch}
+	}
+}
+
+//Scratch returns the scratch value of the iteration that ctx belongs to.
+//the value is made or taken from the values of the finished iterations on the first call.
+//it must not be used after the iteration returns.
+//if ctx has no scratch value, it returns nil
+func Scratch(ctx context.Context) any {
+	s, _ := ctx.Value(scratchKey{}).(*scratchSlot)
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		s.v = s.pool.get()
+		s.taken = true
+	})
+	return s.v
+}
+
+//scratchPool holds the scratch values that are not used by an iteration
+type scratchPool struct {
+	mu   sync.Mutex
+	new  func() any
+	free []any
+}
+
+func (p *scratchPool) get() any {
+	p.mu.Lock()
+	if n := len(p.free); n > 0 {
+		v := p.free[n-1]
+		p.free = p.free[:n-1]
+		p.mu.Unlock()
+		return v
+	}
+	p.mu.Unlock()
+	return p.new()
+}
+
+func (p *scratchPool) put(v any) {
+	p.mu.Lock()
+	p.free = append(p.free, v)
+	p.mu.Unlock()
+}
+
+//scratchSlot is the scratch value of an iteration
+type scratchSlot struct {
+	pool  *scratchPool
+	once  sync.Once
+	v     any
+	taken bool
+}
+
+//release returns the value of the iteration to the pool
+func (s *scratchSlot) release() {
+	s.once.Do(func() {})
+	if s.taken {
+		s.pool.put(s.v)
+	}
+}
+
+//contextWithScratch returns a copy of ctx that carries a scratch slot of p
+//and the function that releases the slot
+func contextWithScratch(ctx context.Context, p *scratchPool) (context.Context, func()) {
+	s := &scratchSlot{pool: p}
+	return context.WithValue(ctx, scratchKey{}, s), s.release
+}
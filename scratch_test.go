@@ -0,0 +1,61 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+type scratchBuffer struct {
+	bytes.Buffer
+	inUse int32
+}
+
+func TestWithScratch(t *testing.T) {
+	var made int32
+	parallel.ForContext(context.Background(), 0, 100, func(ctx context.Context, i int) {
+		s := parallel.Scratch(ctx).(*scratchBuffer)
+		if !atomic.CompareAndSwapInt32(&s.inUse, 0, 1) {
+			t.Error("scratch is shared", i)
+		}
+		if s != parallel.Scratch(ctx) {
+			t.Error("scratch changed", i)
+		}
+		s.Reset()
+		s.WriteString("item")
+		atomic.StoreInt32(&s.inUse, 0)
+	}, parallel.WithWorkers(4), parallel.WithScratch(func() any {
+		atomic.AddInt32(&made, 1)
+		return &scratchBuffer{}
+	}))
+
+	if made < 1 || made > 4 {
+		t.Error(made)
+	}
+}
+
+func TestScratchWithoutOption(t *testing.T) {
+	parallel.ForContext(context.Background(), 0, 2, func(ctx context.Context, i int) {
+		if v := parallel.Scratch(ctx); v != nil {
+			t.Error(v)
+		}
+	})
+	if v := parallel.Scratch(context.Background()); v != nil {
+		t.Error(v)
+	}
+}
+
+func TestScratchUnused(t *testing.T) {
+	var made int32
+	parallel.ForContext(context.Background(), 0, 10, func(ctx context.Context, i int) {
+	}, parallel.WithScratch(func() any {
+		atomic.AddInt32(&made, 1)
+		return nil
+	}))
+	if made != 0 {
+		t.Error(made)
+	}
+}
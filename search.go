@@ -0,0 +1,77 @@
+package parallel
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+//First evaluates pred for the elements of slice in parallel
+//and returns the smallest index whose element matches.
+//once a match is found, elements after it are not evaluated
+//if pred panics, the element does not match
+//
+// i, found := parallel.First(s, func(e int) bool {
+// 		return e > 10
+// })
+func First[T any](slice []T, pred func(T) bool) (index int, found bool) {
+	return FirstWithContext(emptyContext, slice, pred)
+}
+
+//FirstWithContext is [First] that stops evaluating elements when ctx is done.
+//in that case, found is false unless a match was already found
+func FirstWithContext[T any](ctx context.Context, slice []T, pred func(T) bool) (index int, found bool) {
+	length := int64(len(slice))
+	next := int64(-1)
+	match := length
+
+	startWorkers(0, func() {
+		for ctx.Err() == nil {
+			i := atomic.AddInt64(&next, 1)
+			//indices are claimed in order, so no later index can be a better match
+			if i >= atomic.LoadInt64(&match) {
+				return
+			}
+
+			matched := false
+			callRecover(func() {
+				matched = pred(slice[i])
+			})
+			if !matched {
+				continue
+			}
+
+			for {
+				m := atomic.LoadInt64(&match)
+				if i >= m || atomic.CompareAndSwapInt64(&match, m, i) {
+					break
+				}
+			}
+		}
+	}).Wait()
+
+	if match == length {
+		return -1, false
+	}
+	return int(match), true
+}
+
+//AnyMatch reports whether pred is true for any element of slice.
+//it stops as soon as a match is found
+func AnyMatch[T any](slice []T, pred func(T) bool) bool {
+	_, found := First(slice, pred)
+	return found
+}
+
+//AllMatch reports whether pred is true for all elements of slice.
+//it stops as soon as an element does not match.
+//if pred panics, the element does not match
+func AllMatch[T any](slice []T, pred func(T) bool) bool {
+	_, found := First(slice, func(e T) bool {
+		matched := false
+		callRecover(func() {
+			matched = pred(e)
+		})
+		return !matched
+	})
+	return !found
+}
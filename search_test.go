@@ -0,0 +1,85 @@
+package parallel_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestFirst(t *testing.T) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = i
+	}
+
+	var count int32
+	i, found := parallel.First(s, func(e int) bool {
+		atomic.AddInt32(&count, 1)
+		return e%100 == 99
+	})
+
+	if !found || i != 99 {
+		t.Error("require 99", i, found)
+	}
+	if count == int32(len(s)) {
+		t.Error("remaining elements must not be evaluated")
+	}
+}
+
+func TestFirstNotFound(t *testing.T) {
+	i, found := parallel.First([]int{1, 2, 3}, func(e int) bool {
+		return e > 3
+	})
+	if found || i != -1 {
+		t.Error("require not found", i, found)
+	}
+
+	if _, found := parallel.First([]int{}, func(e int) bool { return true }); found {
+		t.Error("empty slice must not be found")
+	}
+}
+
+func TestFirstPanic(t *testing.T) {
+	i, found := parallel.First([]int{1, 2, 3}, func(e int) bool {
+		if e == 1 {
+			panic("hello")
+		}
+		return true
+	})
+	if !found || i != 1 {
+		t.Error("require 1", i, found)
+	}
+}
+
+func TestFirstWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, found := parallel.FirstWithContext(ctx, []int{1}, func(e int) bool { return true }); found {
+		t.Error("canceled search must not be found")
+	}
+}
+
+func TestAnyAllMatch(t *testing.T) {
+	s := []int{2, 4, 6, 7}
+	even := func(e int) bool { return e%2 == 0 }
+
+	if !parallel.AnyMatch(s, even) {
+		t.Error("require any")
+	}
+	if parallel.AllMatch(s, even) {
+		t.Error("7 is not even")
+	}
+	if !parallel.AllMatch(s[:3], even) {
+		t.Error("require all")
+	}
+	if parallel.AllMatch(s[:3], func(e int) bool {
+		if e == 4 {
+			panic("hello")
+		}
+		return true
+	}) {
+		t.Error("a panic does not match")
+	}
+}
@@ -0,0 +1,124 @@
+package parallel
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+//ForEachSeq calls f for each value of seq in parallel using workers goroutines.
+//values are read from seq one at a time, so seq is never copied into a slice.
+//if workers <= 0, runtime.GOMAXPROCS(0) is used
+//
+// parallel.ForEachSeq(maps.Keys(m), 4, func(k string) {
+// 		fmt.Println(k)
+// })
+func ForEachSeq[T any](seq iter.Seq[T], workers int, f func(T)) {
+	ForEachSeqWithContext(emptyContext, seq, workers, f)
+}
+
+//ForEachSeqWithContext calls f for each value of seq in parallel using workers goroutines.
+//when ctx is done, no more values are read from seq
+//and it ends after the values already read are finished
+func ForEachSeqWithContext[T any](ctx context.Context, seq iter.Seq[T], workers int, f func(T), opts ...Option) {
+	cfg := newConfig(opts)
+	if cfg.sequential {
+		workers = 1
+	}
+	workers = workerCount(workers)
+	values := newWorkQueue[T](cfg.queue, workers)
+	wg := startWorkers(workers, func() {
+		for {
+			v, ok := values.pop()
+			if !ok {
+				return
+			}
+			callRecover(func() {
+				f(v)
+			})
+		}
+	})
+
+	for v := range seq {
+		if !values.push(ctx, v) {
+			break
+		}
+	}
+	values.close()
+	wg.Wait()
+}
+
+//ForEachSeq2 calls f for each pair of seq in parallel using workers goroutines.
+//if workers <= 0, runtime.GOMAXPROCS(0) is used
+//
+// parallel.ForEachSeq2(maps.All(m), 4, func(k string, v int) {
+// 		fmt.Println(k, v)
+// })
+func ForEachSeq2[K, V any](seq iter.Seq2[K, V], workers int, f func(K, V)) {
+	ForEachSeq2WithContext(emptyContext, seq, workers, f)
+}
+
+//ForEachSeq2WithContext calls f for each pair of seq in parallel using workers goroutines.
+//when ctx is done, no more pairs are read from seq
+//and it ends after the running calls are finished
+func ForEachSeq2WithContext[K, V any](ctx context.Context, seq iter.Seq2[K, V], workers int, f func(K, V), opts ...Option) {
+	type pair struct {
+		k K
+		v V
+	}
+	ForEachSeqWithContext(ctx, func(yield func(pair) bool) {
+		for k, v := range seq {
+			if !yield(pair{k, v}) {
+				return
+			}
+		}
+	}, workers, func(p pair) {
+		f(p.k, p.v)
+	}, opts...)
+}
+
+//ForEachSyncMap calls f for each key and value of m in parallel using workers goroutines.
+//like m.Range, it does not see a consistent snapshot of m
+//if workers <= 0, runtime.GOMAXPROCS(0) is used
+func ForEachSyncMap(m *sync.Map, workers int, f func(key, value interface{})) {
+	ForEachSyncMapWithContext(emptyContext, m, workers, f)
+}
+
+//ForEachSyncMapWithContext calls f for each key and value of m in parallel using workers goroutines.
+//when ctx is done, the range over m stops
+func ForEachSyncMapWithContext(ctx context.Context, m *sync.Map, workers int, f func(key, value interface{}), opts ...Option) {
+	ForEachSeq2WithContext(ctx, m.Range, workers, f, opts...)
+}
+
+//startWorkers runs worker in n goroutines
+//if n <= 0, runtime.GOMAXPROCS(0) is used
+func startWorkers(n int, worker func()) *sync.WaitGroup {
+	n = workerCount(n)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+	return wg
+}
+
+//workerCount returns n
+//if n <= 0, runtime.GOMAXPROCS(0)
+func workerCount(n int) int {
+	if n <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return n
+}
+
+//callRecover calls f
+//panic will not end the program.
+func callRecover(f func()) {
+	defer defaultRecover()
+	f()
+}
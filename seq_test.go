@@ -0,0 +1,87 @@
+package parallel_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForEachSeq(t *testing.T) {
+	var sum int64
+	parallel.ForEachSeq(slices.Values([]int64{1, 2, 3, 4, 5}), 2, func(v int64) {
+		atomic.AddInt64(&sum, v)
+	})
+
+	if sum != 15 {
+		t.Error("require 15", sum)
+	}
+}
+
+func TestForEachSeqPanic(t *testing.T) {
+	var count int32
+	parallel.ForEachSeq(slices.Values([]int{1, 2, 3}), 1, func(v int) {
+		atomic.AddInt32(&count, 1)
+		if v == 2 {
+			panic("hello")
+		}
+	})
+
+	if count != 3 {
+		t.Error("panic must not stop the worker", count)
+	}
+}
+
+func TestForEachSeqContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var count int32
+	parallel.ForEachSeqWithContext(ctx, func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}, 2, func(v int) {
+		if atomic.AddInt32(&count, 1) == 10 {
+			cancel()
+		}
+	})
+
+	if count < 10 {
+		t.Error("require at least 10", count)
+	}
+}
+
+func TestForEachSeq2(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := sync.Map{}
+	parallel.ForEachSeq2(maps.All(m), 0, func(k string, v int) {
+		result.Store(k, v)
+	})
+
+	for k, v := range m {
+		if r, _ := result.Load(k); r != v {
+			t.Error("require", k, v, r)
+		}
+	}
+}
+
+func TestForEachSyncMap(t *testing.T) {
+	m := sync.Map{}
+	for i := 0; i < 100; i++ {
+		m.Store(i, i)
+	}
+
+	var sum int64
+	parallel.ForEachSyncMap(&m, 4, func(k, v interface{}) {
+		atomic.AddInt64(&sum, int64(v.(int)))
+	})
+
+	if sum != 4950 {
+		t.Error("require 4950", sum)
+	}
+}
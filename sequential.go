@@ -0,0 +1,31 @@
+package parallel
+
+//WithSequential runs the call one item at a time in the order of the input,
+//with the same functions, arguments and results as the parallel call.
+//it is a drop-in switch for migrating a sequential loop:
+//put it behind a flag to compare the results of both implementations
+//or to rule out concurrency while debugging.
+//it overrides [WithWorkers] and is ignored by [WithReplaySchedule], which decides the order itself.
+//[RaceWithOptions] calls the functions in order until one of them succeeds.
+//every function, including those that take no options such as [For] and [Sum],
+//has a sequential counterpart with the same signature in the package github.com/rudty/go-parallel/sequential
+//
+// opts := []parallel.Option{parallel.WithCollectAllErrors(0)}
+// if *sequential {
+// 		opts = append(opts, parallel.WithSequential())
+// }
+// err := parallel.ForErr(ctx, 0, len(rows), migrate, opts...)
+func WithSequential() Option {
+	return func(c *config) {
+		c.sequential = true
+	}
+}
+
+//workerLimit returns the number of workers of a call configured by c.
+//0 means the default of the call
+func (c *config) workerLimit() int {
+	if c.sequential {
+		return 1
+	}
+	return c.workers
+}
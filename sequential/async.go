@@ -0,0 +1,70 @@
+package sequential
+
+import (
+	"context"
+
+	"github.com/rudty/go-parallel"
+)
+
+//AllAsync is [parallel.AllAsync] that calls functions one at a time in order.
+//it still returns without waiting for them
+func AllAsync(ctx context.Context, functions ...ContextTaskFunc) (*Job, context.CancelFunc) {
+	return parallel.AllAsync(ctx, chain(functions, false)...)
+}
+
+//RaceAsync is [parallel.RaceAsync] that calls functions one at a time in order
+//until one of them returns without panicking.
+//it still returns without waiting for them
+func RaceAsync(ctx context.Context, functions ...ContextTaskFunc) (*Job, context.CancelFunc) {
+	return parallel.RaceAsync(ctx, chain(functions, true)...)
+}
+
+//chain returns functions that wait for the function before them.
+//with untilWin, a function that returns without panicking stops the chain:
+//the rest return when the context is done, which the race does when it has a winner
+func chain(functions []ContextTaskFunc, untilWin bool) []ContextTaskFunc {
+	chained := make([]ContextTaskFunc, len(functions))
+	prev := make(chan struct{})
+	close(prev)
+	for i, f := range functions {
+		wait, done := prev, make(chan struct{})
+		chained[i] = func(ctx context.Context) {
+			select {
+			case <-wait:
+			case <-ctx.Done():
+				return
+			}
+
+			won := false
+			defer func() {
+				if !won || !untilWin {
+					close(done)
+				}
+			}()
+			if ctx.Err() != nil {
+				return
+			}
+			f(ctx)
+			won = true
+		}
+		prev = done
+	}
+	return chained
+}
+
+//Go is [parallel.Go] that calls f before it returns
+func Go(f FutureFunc) *Future {
+	future := parallel.Go(f)
+	<-future.Done()
+	return future
+}
+
+//AwaitAll is [parallel.AwaitAll]
+func AwaitAll(futures ...*Future) ([]interface{}, error) {
+	return parallel.AwaitAll(futures...)
+}
+
+//AwaitAny is [parallel.AwaitAny]
+func AwaitAny(futures ...*Future) *Future {
+	return parallel.AwaitAny(futures...)
+}
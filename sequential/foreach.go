@@ -0,0 +1,96 @@
+//go:build !parallel_noreflect
+
+package sequential
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rudty/go-parallel"
+)
+
+//ForEach is [parallel.ForEach] that calls f for the elements of collection in order
+func ForEach(collection interface{}, f interface{}) {
+	ForEachWithContext(emptyContext, collection, f)
+}
+
+//ForEachWithContext is [parallel.ForEachWithContext] that calls f for the elements of collection in order
+func ForEachWithContext(ctx context.Context, collection interface{}, f interface{}) {
+	ForEachWithOptions(ctx, collection, f)
+}
+
+//ForEachWithOptions is [parallel.ForEachWithOptions] that calls f for the elements of collection in order
+func ForEachWithOptions(ctx context.Context, collection interface{}, f interface{}, opts ...Option) {
+	parallel.ForEachWithOptions(ctx, collection, f, sequential(opts)...)
+}
+
+//ForEachSlice is [parallel.ForEachSlice] that calls f for the elements of slice in order
+func ForEachSlice(slice interface{}, f interface{}) {
+	ForEachSliceWithContext(emptyContext, slice, f)
+}
+
+//ForEachSliceWithContext is [parallel.ForEachSliceWithContext] that calls f for the elements of slice in order
+func ForEachSliceWithContext(ctx context.Context, slice interface{}, f interface{}) {
+	ForEachSliceWithOptions(ctx, slice, f)
+}
+
+//ForEachSliceWithOptions is [parallel.ForEachSliceWithOptions] that calls f for the elements of slice in order
+func ForEachSliceWithOptions(ctx context.Context, slice interface{}, f interface{}, opts ...Option) {
+	parallel.ForEachSliceWithOptions(ctx, slice, f, sequential(opts)...)
+}
+
+//ForEachMap is [parallel.ForEachMap] that calls f for one entry of m at a time
+func ForEachMap(m interface{}, f interface{}) {
+	ForEachMapWithContext(emptyContext, m, f)
+}
+
+//ForEachMapWithContext is [parallel.ForEachMapWithContext] that calls f for one entry of m at a time
+func ForEachMapWithContext(ctx context.Context, m interface{}, f interface{}) {
+	ForEachMapWithOptions(ctx, m, f)
+}
+
+//ForEachMapWithOptions is [parallel.ForEachMapWithOptions] that calls f for one entry of m at a time
+func ForEachMapWithOptions(ctx context.Context, m interface{}, f interface{}, opts ...Option) {
+	parallel.ForEachMapWithOptions(ctx, m, f, sequential(opts)...)
+}
+
+//ForEachStruct is [parallel.ForEachStruct] that calls f for the fields of s in order
+func ForEachStruct(s interface{}, f interface{}) {
+	ForEachStructWithContext(emptyContext, s, f)
+}
+
+//ForEachStructWithContext is [parallel.ForEachStructWithContext] that calls f for the fields of s in order
+func ForEachStructWithContext(ctx context.Context, s interface{}, f interface{}) {
+	ForEachStructWithOptions(ctx, s, f)
+}
+
+//ForEachStructWithOptions is [parallel.ForEachStructWithOptions] that calls f for the fields of s in order
+func ForEachStructWithOptions(ctx context.Context, s interface{}, f interface{}, opts ...Option) {
+	parallel.ForEachStructWithOptions(ctx, s, f, sequential(opts)...)
+}
+
+//ForEachZip is [parallel.ForEachZip] that calls f for the elements of a and b in order
+func ForEachZip(a interface{}, b interface{}, f interface{}) {
+	ForEachZipWithContext(emptyContext, a, b, f)
+}
+
+//ForEachZipWithContext is [parallel.ForEachZipWithContext] that calls f for the elements of a and b in order
+func ForEachZipWithContext(ctx context.Context, a interface{}, b interface{}, f interface{}) {
+	ForEachZipWithOptions(ctx, a, b, f)
+}
+
+//ForEachZipWithOptions is [parallel.ForEachZipWithOptions] that calls f for the elements of a and b in order
+func ForEachZipWithOptions(ctx context.Context, a interface{}, b interface{}, f interface{}, opts ...Option) {
+	parallel.ForEachZipWithOptions(ctx, a, b, f, sequential(opts)...)
+}
+
+//ForEachInterruptible is [parallel.ForEachInterruptible] that calls f for the elements of collection in order
+func ForEachInterruptible(collection interface{}, f interface{}) error {
+	ctx, stop := signal.NotifyContext(emptyContext, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ForEachWithOptions(emptyContext, collection, f, parallel.WithLimiter(interruptLimiter{ctx}))
+	return interrupted(emptyContext, ctx)
+}
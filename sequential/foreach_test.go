@@ -0,0 +1,47 @@
+//go:build !parallel_noreflect
+
+package sequential_test
+
+import (
+	"testing"
+
+	"github.com/rudty/go-parallel"
+	"github.com/rudty/go-parallel/sequential"
+)
+
+func TestForEach(t *testing.T) {
+	s := []int{4, 2, 7, 1}
+	var par, seq recorder
+	parallel.ForEach(s, func(i int, e int) { par.call(i) })
+	sequential.ForEach(s, func(i int, e int) { seq.call(i) })
+	compare(t, "ForEach", &par, &seq)
+}
+
+//TestSurfaceReflect is [TestSurface] for the functions that use reflect
+func TestSurfaceReflect(t *testing.T) {
+	same(parallel.ForEach, sequential.ForEach)
+	same(parallel.ForEachWithContext, sequential.ForEachWithContext)
+	same(parallel.ForEachWithOptions, sequential.ForEachWithOptions)
+	same(parallel.ForEachSlice, sequential.ForEachSlice)
+	same(parallel.ForEachSliceWithContext, sequential.ForEachSliceWithContext)
+	same(parallel.ForEachSliceWithOptions, sequential.ForEachSliceWithOptions)
+	same(parallel.ForEachMap, sequential.ForEachMap)
+	same(parallel.ForEachMapWithContext, sequential.ForEachMapWithContext)
+	same(parallel.ForEachMapWithOptions, sequential.ForEachMapWithOptions)
+	same(parallel.ForEachStruct, sequential.ForEachStruct)
+	same(parallel.ForEachStructWithContext, sequential.ForEachStructWithContext)
+	same(parallel.ForEachStructWithOptions, sequential.ForEachStructWithOptions)
+	same(parallel.ForEachZip, sequential.ForEachZip)
+	same(parallel.ForEachZipWithContext, sequential.ForEachZipWithContext)
+	same(parallel.ForEachZipWithOptions, sequential.ForEachZipWithOptions)
+	same(parallel.ForEachInterruptible, sequential.ForEachInterruptible)
+}
+
+func TestForEachZip(t *testing.T) {
+	a := []int{1, 2, 3, 4}
+	b := []string{"a", "b", "c", "d"}
+	var par, seq recorder
+	parallel.ForEachZip(a, b, func(i int, ea int, eb string) { par.call(i) })
+	sequential.ForEachZip(a, b, func(i int, ea int, eb string) { seq.call(i) })
+	compare(t, "ForEachZip", &par, &seq)
+}
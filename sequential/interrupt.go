@@ -0,0 +1,55 @@
+package sequential
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rudty/go-parallel"
+)
+
+//interruptLimiter is a [parallel.Limiter] that admits no iteration after ctx is done
+type interruptLimiter struct {
+	ctx context.Context
+}
+
+func (l interruptLimiter) Wait(ctx context.Context) error {
+	if err := l.ctx.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+//interrupted returns the error of parent, or parallel.ErrInterrupted if only ctx is done
+func interrupted(parent context.Context, ctx context.Context) error {
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return parallel.ErrInterrupted
+	}
+	return nil
+}
+
+//ForInterruptible is [parallel.ForInterruptible] that calls f from begin to end in order
+func ForInterruptible(begin int, end int, f ForLoop) error {
+	return ForInterruptibleContext(emptyContext, begin, end, func(ctx context.Context, i int) {
+		f(i)
+	})
+}
+
+//ForInterruptibleContext is [parallel.ForInterruptibleContext] that calls f from begin to end in order
+func ForInterruptibleContext(c context.Context, begin int, end int, f ContextForLoop) error {
+	ctx, stop := signal.NotifyContext(c, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	parallel.ForContext(context.WithoutCancel(c), begin, end, func(iterCtx context.Context, i int) {
+		iterCtx, cancel := context.WithCancel(iterCtx)
+		defer cancel()
+		defer context.AfterFunc(ctx, cancel)()
+
+		f(iterCtx, i)
+	}, parallel.WithLimiter(interruptLimiter{ctx}), parallel.WithSequential())
+	return interrupted(c, ctx)
+}
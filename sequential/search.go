@@ -0,0 +1,79 @@
+package sequential
+
+import (
+	"context"
+
+	"github.com/rudty/go-parallel"
+)
+
+//First is [parallel.First] that evaluates pred for the elements of slice in order
+//and stops at the first match.
+//if pred panics, the element does not match
+func First[T any](slice []T, pred func(T) bool) (index int, found bool) {
+	return FirstWithContext(emptyContext, slice, pred)
+}
+
+//FirstWithContext is [parallel.FirstWithContext] that evaluates pred for the elements of slice in order
+func FirstWithContext[T any](ctx context.Context, slice []T, pred func(T) bool) (index int, found bool) {
+	for i, e := range slice {
+		if ctx.Err() != nil {
+			break
+		}
+		if matches(pred, e) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+//matches calls pred and reports false if it panics
+func matches[T any](pred func(T) bool, e T) (matched bool) {
+	defer printRecover()
+	return pred(e)
+}
+
+//AnyMatch is [parallel.AnyMatch] that stops at the first match in order
+func AnyMatch[T any](slice []T, pred func(T) bool) bool {
+	_, found := First(slice, pred)
+	return found
+}
+
+//AllMatch is [parallel.AllMatch] that stops at the first element in order that does not match
+func AllMatch[T any](slice []T, pred func(T) bool) bool {
+	_, found := First(slice, func(e T) bool {
+		return !matches(pred, e)
+	})
+	return !found
+}
+
+//Sum is [parallel.Sum] that adds the elements of slice in order
+func Sum[T parallel.Number](slice []T) T {
+	return parallel.Collect(slice, parallel.SumCollector[T](), parallel.WithSequential())
+}
+
+//Count is [parallel.Count] that counts the elements of slice in order
+func Count[T any](slice []T, pred func(T) bool) int {
+	return parallel.Collect(slice, parallel.CountCollector(pred), parallel.WithSequential())
+}
+
+//GroupBy is [parallel.GroupBy] that groups the elements of slice in order
+func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
+	return parallel.Collect(slice, parallel.GroupByCollector(keyFn), parallel.WithSequential())
+}
+
+//Collect is [parallel.Collect] that adds the elements of slice in order to a single accumulator
+func Collect[T, A, R any](slice []T, c parallel.Collector[T, A, R], opts ...Option) R {
+	return parallel.Collect(slice, c, sequential(opts)...)
+}
+
+//ForEachAccum is [parallel.ForEachAccum] that calls f for the elements of s in order with a single accumulator
+func ForEachAccum[T, A any](s []T, newAccum func() A, f func(a A, e T), merge func(A, A) A) A {
+	return Collect(s, parallel.CollectorFuncs[T, A, A]{
+		NewFunc: newAccum,
+		AddFunc: func(acc A, e T) A {
+			f(acc, e)
+			return acc
+		},
+		MergeFunc: merge,
+	})
+}
@@ -0,0 +1,211 @@
+//Package sequential has the functions of github.com/rudty/go-parallel
+//with the same signatures, but each call runs one item at a time in the order of the input.
+//it is the drop-in counterpart of [parallel.WithSequential]:
+//switch the import to compare the results of both implementations
+//or to rule out concurrency while debugging.
+//the functions that take options append [parallel.WithSequential] to them,
+//so it overrides [parallel.WithWorkers]
+//
+// import parallel "github.com/rudty/go-parallel/sequential"
+//
+// parallel.For(0, len(rows), func(i int) {
+// 		migrate(rows[i])
+// })
+package sequential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rudty/go-parallel"
+)
+
+//the types of the parameters, so the callers compile with either import.
+//the generic types such as parallel.Result are used from parallel
+type (
+	ForLoop         = parallel.ForLoop
+	ErrLoop         = parallel.ErrLoop
+	ContextForLoop  = parallel.ContextForLoop
+	ContextErrLoop  = parallel.ContextErrLoop
+	TaskFunc        = parallel.TaskFunc
+	ContextTaskFunc = parallel.ContextTaskFunc
+	StringLoop      = parallel.StringLoop
+	FutureFunc      = parallel.FutureFunc
+	Future          = parallel.Future
+	Job             = parallel.Job
+	Option          = parallel.Option
+	Policy          = parallel.Policy
+	Backoff         = parallel.Backoff
+	Report          = parallel.Report
+	Number          = parallel.Number
+)
+
+var emptyContext = context.Background()
+
+//printRecover writes a panic to os.Stderr like the functions of parallel
+func printRecover() {
+	if r := recover(); r != nil {
+		fmt.Fprintln(os.Stderr, r)
+	}
+}
+
+//sequential returns opts with [parallel.WithSequential] appended.
+//the slice of the caller is not changed
+func sequential(opts []Option) []Option {
+	return append(opts[:len(opts):len(opts)], parallel.WithSequential())
+}
+
+//For is [parallel.For] that calls f from begin to end in order
+func For(begin int, end int, f ForLoop) {
+	ForWithContext(emptyContext, begin, end, f)
+}
+
+//ForWithContext is [parallel.ForWithContext] that calls f from begin to end in order
+func ForWithContext(c context.Context, begin int, end int, f ForLoop) {
+	ForWithOptions(c, begin, end, f)
+}
+
+//ForWithOptions is [parallel.ForWithOptions] that calls f from begin to end in order
+func ForWithOptions(c context.Context, begin int, end int, f ForLoop, opts ...Option) {
+	parallel.ForWithOptions(c, begin, end, f, sequential(opts)...)
+}
+
+//ForStrict is [parallel.ForStrict] that calls f from begin to end in order
+func ForStrict(c context.Context, begin int, end int, f ForLoop, opts ...Option) error {
+	return parallel.ForStrict(c, begin, end, f, sequential(opts)...)
+}
+
+//ForContext is [parallel.ForContext] that calls f from begin to end in order
+func ForContext(c context.Context, begin int, end int, f ContextForLoop, opts ...Option) {
+	parallel.ForContext(c, begin, end, f, sequential(opts)...)
+}
+
+//ForErr is [parallel.ForErr] that calls f from begin to end in order
+func ForErr(ctx context.Context, begin int, end int, f ErrLoop, opts ...Option) error {
+	return parallel.ForErr(ctx, begin, end, f, sequential(opts)...)
+}
+
+//ForErrContext is [parallel.ForErrContext] that calls f from begin to end in order
+func ForErrContext(c context.Context, begin int, end int, f ContextErrLoop, opts ...Option) error {
+	return parallel.ForErrContext(c, begin, end, f, sequential(opts)...)
+}
+
+//All is [parallel.All] that calls functions in order
+func All(functions ...TaskFunc) {
+	AllWithContext(emptyContext, functions...)
+}
+
+//AllWithContext is [parallel.AllWithContext] that calls functions in order
+func AllWithContext(ctx context.Context, functions ...TaskFunc) {
+	ForWithContext(ctx, 0, len(functions), func(i int) {
+		functions[i]()
+	})
+}
+
+//AllWithPolicy is [parallel.AllWithPolicy] that calls functions in order
+func AllWithPolicy(ctx context.Context, p Policy, functions ...TaskFunc) error {
+	return AllWithOptions(ctx, functions, parallel.WithPolicy(p))
+}
+
+//AllWithOptions is [parallel.AllWithOptions] that calls functions in order
+func AllWithOptions(ctx context.Context, functions []TaskFunc, opts ...Option) error {
+	return parallel.AllWithOptions(ctx, functions, sequential(opts)...)
+}
+
+//Race is [parallel.Race] run one function at a time.
+//the functions are called in order until one of them returns without panicking
+func Race(functions ...TaskFunc) {
+	RaceWithContext(emptyContext, functions...)
+}
+
+//RaceWithContext is [parallel.RaceWithContext] run one function at a time.
+//the functions are called in order until one of them returns without panicking or ctx is done
+func RaceWithContext(ctx context.Context, functions ...TaskFunc) {
+	for _, f := range functions {
+		if ctx.Err() != nil || finished(f) {
+			return
+		}
+	}
+}
+
+//finished calls f and reports whether it returned without panicking
+func finished(f TaskFunc) (ok bool) {
+	defer printRecover()
+	f()
+	return true
+}
+
+//RaceWithCancel is [parallel.RaceWithCancel] run one function at a time.
+//the functions are called in order until one of them returns without panicking or ctx is done.
+//the context passed to a function is canceled when it returns
+func RaceWithCancel(ctx context.Context, functions ...ContextTaskFunc) {
+	for _, f := range functions {
+		if ctx.Err() != nil {
+			return
+		}
+		won := finished(func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			f(ctx)
+		})
+		if won {
+			return
+		}
+	}
+}
+
+//RaceWithPolicy is [parallel.RaceWithPolicy] that calls functions in order until one of them succeeds
+func RaceWithPolicy(ctx context.Context, p Policy, functions ...TaskFunc) error {
+	return RaceWithOptions(ctx, functions, parallel.WithPolicy(p))
+}
+
+//RaceWithOptions is [parallel.RaceWithOptions] that calls functions in order until one of them succeeds
+func RaceWithOptions(ctx context.Context, functions []TaskFunc, opts ...Option) error {
+	return parallel.RaceWithOptions(ctx, functions, sequential(opts)...)
+}
+
+//RaceRetry is [parallel.RaceRetry] that runs fs in order until one of them succeeds.
+//each participant retries with the delay of policy before the next one starts
+func RaceRetry[T any](ctx context.Context, policy Backoff, fs ...func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	var errs []error
+	for i, f := range fs {
+		v, err := parallel.RaceRetry(ctx, policy, f)
+		if err == nil {
+			return v, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return zero, ctxErr
+		}
+		errs = append(errs, fmt.Errorf("task %d: %w", i, participantErr(err)))
+	}
+	return zero, errors.Join(errs...)
+}
+
+//participantErr returns the error of the only participant of a race,
+//which is joined with its index
+func participantErr(err error) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && len(joined.Unwrap()) == 1 {
+		if inner := errors.Unwrap(joined.Unwrap()[0]); inner != nil {
+			return inner
+		}
+	}
+	return err
+}
+
+//Series is [parallel.Series] that calls the functions of each group in order
+func Series(groups ...[]TaskFunc) error {
+	return SeriesWithOptions(emptyContext, groups)
+}
+
+//Phases is [parallel.Phases] that calls the functions of each phase in order
+func Phases(ctx context.Context, phases ...[]TaskFunc) error {
+	return SeriesWithOptions(ctx, phases)
+}
+
+//SeriesWithOptions is [parallel.SeriesWithOptions] that calls the functions of each group in order
+func SeriesWithOptions(ctx context.Context, groups [][]TaskFunc, opts ...Option) error {
+	return parallel.SeriesWithOptions(ctx, groups, sequential(opts)...)
+}
@@ -0,0 +1,230 @@
+package sequential_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+	"github.com/rudty/go-parallel/sequential"
+)
+
+//recorder records the calls of a function and the most calls that ran at once
+type recorder struct {
+	mu      sync.Mutex
+	calls   []int
+	running int32
+	most    int32
+}
+
+func (r *recorder) call(i int) {
+	if n := atomic.AddInt32(&r.running, 1); n > atomic.LoadInt32(&r.most) {
+		atomic.StoreInt32(&r.most, n)
+	}
+	defer atomic.AddInt32(&r.running, -1)
+	time.Sleep(time.Millisecond)
+
+	r.mu.Lock()
+	r.calls = append(r.calls, i)
+	r.mu.Unlock()
+}
+
+//sorted returns the calls sorted
+func (r *recorder) sorted() []int {
+	s := append([]int(nil), r.calls...)
+	sort.Ints(s)
+	return s
+}
+
+//compare checks that seq made the same calls as par, in order and one at a time
+func compare(t *testing.T, name string, par, seq *recorder) {
+	t.Helper()
+	if !reflect.DeepEqual(par.sorted(), seq.calls) {
+		t.Error(name, "require the calls of parallel in order", par.calls, seq.calls)
+	}
+	if seq.most != 1 {
+		t.Error(name, "require one call at a time", seq.most)
+	}
+}
+
+func TestFor(t *testing.T) {
+	var par, seq recorder
+	parallel.For(0, 20, par.call)
+	sequential.For(0, 20, seq.call)
+	compare(t, "For", &par, &seq)
+}
+
+func TestAll(t *testing.T) {
+	var par, seq recorder
+	tasks := func(r *recorder) []parallel.TaskFunc {
+		var functions []parallel.TaskFunc
+		for i := 0; i < 10; i++ {
+			i := i
+			functions = append(functions, func() { r.call(i) })
+		}
+		return functions
+	}
+	parallel.All(tasks(&par)...)
+	sequential.All(tasks(&seq)...)
+	compare(t, "All", &par, &seq)
+}
+
+func TestRace(t *testing.T) {
+	var calls []int
+	sequential.Race(func() {
+		calls = append(calls, 0)
+	}, func() {
+		calls = append(calls, 1)
+	})
+	if !reflect.DeepEqual(calls, []int{0}) {
+		t.Error("require the first function", calls)
+	}
+
+	canceled := false
+	sequential.RaceWithCancel(context.Background(), func(ctx context.Context) {
+		defer func() {
+			canceled = ctx.Err() != nil
+		}()
+	})
+	sequential.RaceWithCancel(context.Background())
+	if canceled {
+		t.Error("the context must not be canceled while the function runs")
+	}
+}
+
+func TestForEachOf(t *testing.T) {
+	s := []int{5, 3, 8, 1, 9, 2}
+	var par, seq recorder
+	parallel.ForEachOf(s, func(i int, e int) { par.call(e) })
+	sequential.ForEachOf(s, func(i int, e int) { seq.call(e) })
+	if !reflect.DeepEqual(seq.calls, s) || seq.most != 1 {
+		t.Error("require the elements in order", seq.calls)
+	}
+	if !reflect.DeepEqual(par.sorted(), seq.sorted()) {
+		t.Error("require the same elements", par.calls, seq.calls)
+	}
+
+	m := map[int]int{1: 10, 2: 20, 3: 30}
+	var mpar, mseq recorder
+	parallel.ForEachMapOf(m, func(k int, v int) { mpar.call(k + v) })
+	sequential.ForEachMapOf(m, func(k int, v int) { mseq.call(k + v) })
+	if !reflect.DeepEqual(mpar.sorted(), mseq.sorted()) || mseq.most != 1 {
+		t.Error("require the same entries one at a time", mpar.calls, mseq.calls)
+	}
+}
+
+func TestForEachString(t *testing.T) {
+	var par, seq recorder
+	parallel.ForEachString("héllo", func(i int, r rune) { par.call(i) })
+	sequential.ForEachString("héllo", func(i int, r rune) { seq.call(i) })
+	compare(t, "ForEachString", &par, &seq)
+}
+
+//newSource returns a closed source of [0, n)
+func newSource(n int) *parallel.AppendableSource[int] {
+	src := parallel.NewAppendableSource[int]()
+	for i := 0; i < n; i++ {
+		src.Append(i)
+	}
+	src.Close()
+	return src
+}
+
+func TestDrain(t *testing.T) {
+	var par, seq recorder
+	parallel.Drain(context.Background(), newSource(10), 4, par.call)
+	sequential.Drain(context.Background(), newSource(10), 4, seq.call)
+	compare(t, "Drain", &par, &seq)
+}
+
+func TestSearch(t *testing.T) {
+	s := []int{1, 3, 5, 6, 7, 8}
+	even := func(e int) bool { return e%2 == 0 }
+	positive := func(e int) bool { return e > 0 }
+
+	pi, pf := parallel.First(s, even)
+	si, sf := sequential.First(s, even)
+	if pi != si || pf != sf {
+		t.Error("First", pi, pf, si, sf)
+	}
+	if parallel.AnyMatch(s, even) != sequential.AnyMatch(s, even) {
+		t.Error("AnyMatch")
+	}
+	if parallel.AllMatch(s, even) != sequential.AllMatch(s, even) ||
+		parallel.AllMatch(s, positive) != sequential.AllMatch(s, positive) {
+		t.Error("AllMatch")
+	}
+	if sequential.AllMatch(s, func(e int) bool {
+		if e == 5 {
+			panic("hello")
+		}
+		return true
+	}) {
+		t.Error("AllMatch: a panic does not match")
+	}
+
+	var evaluated []int
+	sequential.First(s, func(e int) bool {
+		evaluated = append(evaluated, e)
+		if e == 3 {
+			panic("does not match")
+		}
+		return even(e)
+	})
+	if !reflect.DeepEqual(evaluated, []int{1, 3, 5, 6}) {
+		t.Error("require the elements in order until the match", evaluated)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	odd := func(e int) bool { return e%2 == 1 }
+	mod3 := func(e int) int { return e % 3 }
+
+	if p, q := parallel.Sum(s), sequential.Sum(s); p != q {
+		t.Error("Sum", p, q)
+	}
+	if p, q := parallel.Count(s, odd), sequential.Count(s, odd); p != q {
+		t.Error("Count", p, q)
+	}
+	if p, q := parallel.GroupBy(s, mod3), sequential.GroupBy(s, mod3); !reflect.DeepEqual(p, q) {
+		t.Error("GroupBy", p, q)
+	}
+}
+
+func TestForDuration(t *testing.T) {
+	var seq recorder
+	n := sequential.ForDuration(20*time.Millisecond, 8, seq.call)
+	if n == 0 || len(seq.calls) != n || seq.most != 1 {
+		t.Error("require the calls one at a time", n, len(seq.calls), seq.most)
+	}
+	for i, it := range seq.calls {
+		if it != i {
+			t.Fatal("require the iterations in order", seq.calls)
+		}
+	}
+}
+
+func TestLoadGen(t *testing.T) {
+	var running, most int32
+	r := sequential.LoadGen{
+		Duration: 20 * time.Millisecond,
+		Workers:  8,
+		F: func(ctx context.Context) error {
+			if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&most) {
+				atomic.StoreInt32(&most, n)
+			}
+			defer atomic.AddInt32(&running, -1)
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+	}.Run(context.Background())
+
+	if r.Requests == 0 || most != 1 {
+		t.Error("require the calls one at a time", r.Requests, most)
+	}
+}
@@ -0,0 +1,154 @@
+package sequential_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+	"github.com/rudty/go-parallel/sequential"
+)
+
+//same compiles only if both functions have the same type
+func same[F any](F, F) {}
+
+//TestSurface checks that every function has the signature of parallel,
+//so a caller compiles with either import
+func TestSurface(t *testing.T) {
+	same(parallel.For, sequential.For)
+	same(parallel.ForWithContext, sequential.ForWithContext)
+	same(parallel.ForWithOptions, sequential.ForWithOptions)
+	same(parallel.ForStrict, sequential.ForStrict)
+	same(parallel.ForContext, sequential.ForContext)
+	same(parallel.ForErr, sequential.ForErr)
+	same(parallel.ForErrContext, sequential.ForErrContext)
+	same(parallel.ForInterruptible, sequential.ForInterruptible)
+	same(parallel.ForInterruptibleContext, sequential.ForInterruptibleContext)
+
+	same(parallel.All, sequential.All)
+	same(parallel.AllWithContext, sequential.AllWithContext)
+	same(parallel.AllWithPolicy, sequential.AllWithPolicy)
+	same(parallel.AllWithOptions, sequential.AllWithOptions)
+	same(parallel.AllAsync, sequential.AllAsync)
+	same(parallel.Race, sequential.Race)
+	same(parallel.RaceWithContext, sequential.RaceWithContext)
+	same(parallel.RaceWithCancel, sequential.RaceWithCancel)
+	same(parallel.RaceWithPolicy, sequential.RaceWithPolicy)
+	same(parallel.RaceWithOptions, sequential.RaceWithOptions)
+	same(parallel.RaceAsync, sequential.RaceAsync)
+	same(parallel.RaceRetry[int], sequential.RaceRetry[int])
+	same(parallel.Series, sequential.Series)
+	same(parallel.Phases, sequential.Phases)
+	same(parallel.SeriesWithOptions, sequential.SeriesWithOptions)
+	same(parallel.Go, sequential.Go)
+	same(parallel.AwaitAll, sequential.AwaitAll)
+	same(parallel.AwaitAny, sequential.AwaitAny)
+
+	same(parallel.ForEachOf[int], sequential.ForEachOf[int])
+	same(parallel.ForEachOfWithOptions[int], sequential.ForEachOfWithOptions[int])
+	same(parallel.ForEachIndexOf[int], sequential.ForEachIndexOf[int])
+	same(parallel.ForEachIndexOfWithOptions[int], sequential.ForEachIndexOfWithOptions[int])
+	same(parallel.ForEachMapOf[string, int], sequential.ForEachMapOf[string, int])
+	same(parallel.ForEachMapOfWithOptions[string, int], sequential.ForEachMapOfWithOptions[string, int])
+	same(parallel.ForEachMapOfErr[string, int], sequential.ForEachMapOfErr[string, int])
+	same(parallel.ForEachZipOf[int, string], sequential.ForEachZipOf[int, string])
+	same(parallel.ForEachJoinMaps[string, int, bool], sequential.ForEachJoinMaps[string, int, bool])
+	same(parallel.ForEachJoinMapsWithContext[string, int, bool], sequential.ForEachJoinMapsWithContext[string, int, bool])
+	same(parallel.ForEachString, sequential.ForEachString)
+	same(parallel.ForEachStringWithContext, sequential.ForEachStringWithContext)
+	same(parallel.ForEachSeq[int], sequential.ForEachSeq[int])
+	same(parallel.ForEachSeqWithContext[int], sequential.ForEachSeqWithContext[int])
+	same(parallel.ForEachSeq2[int, string], sequential.ForEachSeq2[int, string])
+	same(parallel.ForEachSeq2WithContext[int, string], sequential.ForEachSeq2WithContext[int, string])
+	same(parallel.ForEachSyncMap, sequential.ForEachSyncMap)
+	same(parallel.ForEachSyncMapWithContext, sequential.ForEachSyncMapWithContext)
+	same(parallel.Drain[int], sequential.Drain[int])
+	same(parallel.ForDuration, sequential.ForDuration)
+	same(parallel.ForDurationWithContext, sequential.ForDurationWithContext)
+	same(parallel.LoadGen{}.Run, sequential.LoadGen{}.Run)
+
+	same(parallel.Reconcile[string, int], sequential.Reconcile[string, int])
+	same(parallel.ReconcileFunc[string, int], sequential.ReconcileFunc[string, int])
+	same(parallel.WalkTree[int], sequential.WalkTree[int])
+	same(parallel.Map[int, string], sequential.Map[int, string])
+	same(parallel.MapMap[string, int, bool], sequential.MapMap[string, int, bool])
+	same(parallel.MapKeys[string, int, bool], sequential.MapKeys[string, int, bool])
+	same(parallel.MapValues[string, int, bool], sequential.MapValues[string, int, bool])
+	same(parallel.MapStream[int, string], sequential.MapStream[int, string])
+	same(parallel.MapOrdered[int, string], sequential.MapOrdered[int, string])
+	same(parallel.MapUnordered[int, string], sequential.MapUnordered[int, string])
+
+	same(parallel.First[int], sequential.First[int])
+	same(parallel.FirstWithContext[int], sequential.FirstWithContext[int])
+	same(parallel.AnyMatch[int], sequential.AnyMatch[int])
+	same(parallel.AllMatch[int], sequential.AllMatch[int])
+	same(parallel.Collect[int, int, int], sequential.Collect[int, int, int])
+	same(parallel.ForEachAccum[int, *int], sequential.ForEachAccum[int, *int])
+	same(parallel.Sum[int], sequential.Sum[int])
+	same(parallel.Count[int], sequential.Count[int])
+	same(parallel.GroupBy[int, string], sequential.GroupBy[int, string])
+}
+
+func TestRaceWithContext(t *testing.T) {
+	var calls []int
+	sequential.RaceWithContext(context.Background(), func() {
+		calls = append(calls, 0)
+		panic("lose")
+	}, func() {
+		calls = append(calls, 1)
+	}, func() {
+		calls = append(calls, 2)
+	})
+	if len(calls) != 2 || calls[0] != 0 || calls[1] != 1 {
+		t.Error("require the functions in order until one finishes", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = nil
+	sequential.RaceWithContext(ctx, func() {
+		calls = append(calls, 0)
+		cancel()
+		panic("lose")
+	}, func() {
+		calls = append(calls, 1)
+	})
+	if len(calls) != 1 {
+		t.Error("require no function after ctx is done", calls)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	var seq recorder
+	var functions []parallel.ContextTaskFunc
+	for i := 0; i < 10; i++ {
+		i := i
+		functions = append(functions, func(ctx context.Context) { seq.call(i) })
+	}
+	job, cancel := sequential.AllAsync(context.Background(), functions...)
+	defer cancel()
+	if err := job.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if seq.most != 1 || len(seq.calls) != 10 {
+		t.Error("require the calls one at a time", seq.calls)
+	}
+	for i, e := range seq.calls {
+		if e != i {
+			t.Fatal("require the calls in order", seq.calls)
+		}
+	}
+
+	var calls []int
+	job, cancel = sequential.RaceAsync(context.Background(), func(ctx context.Context) {
+		calls = append(calls, 0)
+		panic("lose")
+	}, func(ctx context.Context) {
+		calls = append(calls, 1)
+	}, func(ctx context.Context) {
+		calls = append(calls, 2)
+	})
+	defer cancel()
+	job.Wait()
+	if len(calls) != 2 || calls[0] != 0 || calls[1] != 1 {
+		t.Error("require the functions in order until one finishes", calls)
+	}
+}
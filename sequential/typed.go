@@ -0,0 +1,186 @@
+package sequential
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+//ForEachOf is [parallel.ForEachOf] that calls f for the elements of slice in order
+func ForEachOf[T any](slice []T, f parallel.ElemFunc[T]) {
+	ForEachOfWithOptions(emptyContext, slice, f)
+}
+
+//ForEachOfWithOptions is [parallel.ForEachOfWithOptions] that calls f for the elements of slice in order
+func ForEachOfWithOptions[T any](ctx context.Context, slice []T, f parallel.ElemFunc[T], opts ...Option) {
+	parallel.ForEachOfWithOptions(ctx, slice, f, sequential(opts)...)
+}
+
+//ForEachIndexOf is [parallel.ForEachIndexOf] that calls f for the indexes of slice in order
+func ForEachIndexOf[T any](slice []T, f parallel.IndexFunc) {
+	ForEachIndexOfWithOptions(emptyContext, slice, f)
+}
+
+//ForEachIndexOfWithOptions is [parallel.ForEachIndexOfWithOptions] that calls f for the indexes of slice in order
+func ForEachIndexOfWithOptions[T any](ctx context.Context, slice []T, f parallel.IndexFunc, opts ...Option) {
+	parallel.ForEachIndexOfWithOptions(ctx, slice, f, sequential(opts)...)
+}
+
+//ForEachMapOf is [parallel.ForEachMapOf] that calls f for one entry of m at a time
+func ForEachMapOf[K comparable, V any](m map[K]V, f parallel.EntryFunc[K, V]) {
+	ForEachMapOfWithOptions(emptyContext, m, f)
+}
+
+//ForEachMapOfWithOptions is [parallel.ForEachMapOfWithOptions] that calls f for one entry of m at a time
+func ForEachMapOfWithOptions[K comparable, V any](ctx context.Context, m map[K]V, f parallel.EntryFunc[K, V], opts ...Option) {
+	parallel.ForEachMapOfWithOptions(ctx, m, f, sequential(opts)...)
+}
+
+//ForEachMapOfErr is [parallel.ForEachMapOfErr] that calls f for one entry of m at a time
+func ForEachMapOfErr[K comparable, V any](ctx context.Context, m map[K]V, f func(k K, v V) error, opts ...Option) error {
+	return parallel.ForEachMapOfErr(ctx, m, f, sequential(opts)...)
+}
+
+//ForEachZipOf is [parallel.ForEachZipOf] that calls f for the elements of a and b in order
+func ForEachZipOf[A, B any](a []A, b []B, f func(i int, ea A, eb B), opts ...Option) error {
+	return parallel.ForEachZipOf(a, b, f, sequential(opts)...)
+}
+
+//ForEachJoinMaps is [parallel.ForEachJoinMaps] that calls f for one key at a time
+func ForEachJoinMaps[K comparable, A, B any](a map[K]A, b map[K]B, f func(k K, a A, ok1 bool, b B, ok2 bool), opts ...Option) {
+	ForEachJoinMapsWithContext(emptyContext, a, b, f, opts...)
+}
+
+//ForEachJoinMapsWithContext is [parallel.ForEachJoinMapsWithContext] that calls f for one key at a time
+func ForEachJoinMapsWithContext[K comparable, A, B any](ctx context.Context, a map[K]A, b map[K]B, f func(k K, a A, ok1 bool, b B, ok2 bool), opts ...Option) {
+	parallel.ForEachJoinMapsWithContext(ctx, a, b, f, sequential(opts)...)
+}
+
+//ForEachString is [parallel.ForEachString] that calls f for the runes of s in order
+func ForEachString(s string, f StringLoop, opts ...Option) {
+	ForEachStringWithContext(emptyContext, s, f, opts...)
+}
+
+//ForEachStringWithContext is [parallel.ForEachStringWithContext] that calls f for the runes of s in order
+func ForEachStringWithContext(ctx context.Context, s string, f StringLoop, opts ...Option) {
+	parallel.ForEachStringWithContext(ctx, s, f, sequential(opts)...)
+}
+
+//ForEachSeq is [parallel.ForEachSeq] that calls f for the values of seq in order.
+//workers is ignored
+func ForEachSeq[T any](seq iter.Seq[T], workers int, f func(T)) {
+	ForEachSeqWithContext(emptyContext, seq, workers, f)
+}
+
+//ForEachSeqWithContext is [parallel.ForEachSeqWithContext] that calls f for the values of seq in order.
+//workers is ignored
+func ForEachSeqWithContext[T any](ctx context.Context, seq iter.Seq[T], workers int, f func(T), opts ...Option) {
+	parallel.ForEachSeqWithContext(ctx, seq, 1, f, sequential(opts)...)
+}
+
+//ForEachSeq2 is [parallel.ForEachSeq2] that calls f for the pairs of seq in order.
+//workers is ignored
+func ForEachSeq2[K, V any](seq iter.Seq2[K, V], workers int, f func(K, V)) {
+	ForEachSeq2WithContext(emptyContext, seq, workers, f)
+}
+
+//ForEachSeq2WithContext is [parallel.ForEachSeq2WithContext] that calls f for the pairs of seq in order.
+//workers is ignored
+func ForEachSeq2WithContext[K, V any](ctx context.Context, seq iter.Seq2[K, V], workers int, f func(K, V), opts ...Option) {
+	parallel.ForEachSeq2WithContext(ctx, seq, 1, f, sequential(opts)...)
+}
+
+//ForEachSyncMap is [parallel.ForEachSyncMap] that calls f for one key of m at a time.
+//workers is ignored
+func ForEachSyncMap(m *sync.Map, workers int, f func(key, value interface{})) {
+	ForEachSyncMapWithContext(emptyContext, m, workers, f)
+}
+
+//ForEachSyncMapWithContext is [parallel.ForEachSyncMapWithContext] that calls f for one key of m at a time.
+//workers is ignored
+func ForEachSyncMapWithContext(ctx context.Context, m *sync.Map, workers int, f func(key, value interface{}), opts ...Option) {
+	parallel.ForEachSyncMapWithContext(ctx, m, 1, f, sequential(opts)...)
+}
+
+//Drain is [parallel.Drain] that consumes the items of src in order.
+//workers is ignored
+func Drain[T any](ctx context.Context, src *parallel.AppendableSource[T], workers int, f func(T)) {
+	parallel.Drain(ctx, src, 1, f)
+}
+
+//ForDuration is [parallel.ForDuration] that calls f one call at a time.
+//workers is ignored
+func ForDuration(d time.Duration, workers int, f func(iteration int)) int {
+	return ForDurationWithContext(emptyContext, d, workers, f)
+}
+
+//ForDurationWithContext is [parallel.ForDurationWithContext] that calls f one call at a time.
+//workers is ignored
+func ForDurationWithContext(ctx context.Context, d time.Duration, workers int, f func(iteration int)) int {
+	return parallel.ForDurationWithContext(ctx, d, 1, f)
+}
+
+//LoadGen is [parallel.LoadGen] that calls F one call at a time.
+//Workers is ignored
+type LoadGen parallel.LoadGen
+
+//Run is [parallel.LoadGen.Run] with a single worker
+func (g LoadGen) Run(ctx context.Context) Report {
+	g.Workers = 1
+	return parallel.LoadGen(g).Run(ctx)
+}
+
+//Reconcile is [parallel.Reconcile] that applies one change at a time
+func Reconcile[K comparable, T comparable](ctx context.Context, desired, actual map[K]T,
+	create, update, delete func(ctx context.Context, k K, v T) error, opts ...Option) error {
+	return parallel.Reconcile(ctx, desired, actual, create, update, delete, sequential(opts)...)
+}
+
+//ReconcileFunc is [parallel.ReconcileFunc] that applies one change at a time
+func ReconcileFunc[K comparable, T any](ctx context.Context, desired, actual map[K]T, equal func(a, b T) bool,
+	create, update, delete func(ctx context.Context, k K, v T) error, opts ...Option) error {
+	return parallel.ReconcileFunc(ctx, desired, actual, equal, create, update, delete, sequential(opts)...)
+}
+
+//WalkTree is [parallel.WalkTree] that visits one node at a time
+func WalkTree[T comparable](ctx context.Context, root T, children func(T) []T, visit func(T) error, opts ...Option) error {
+	return parallel.WalkTree(ctx, root, children, visit, sequential(opts)...)
+}
+
+//Map is [parallel.Map] that calls f for the elements of slice in order
+func Map[T, R any](slice []T, f func(T) R, opts ...Option) []R {
+	return parallel.Map(slice, f, sequential(opts)...)
+}
+
+//MapMap is [parallel.MapMap] that calls f for one entry of m at a time
+func MapMap[K comparable, V, R any](m map[K]V, f func(K, V) R, opts ...Option) []R {
+	return parallel.MapMap(m, f, sequential(opts)...)
+}
+
+//MapKeys is [parallel.MapKeys] that calls f for one key of m at a time
+func MapKeys[K comparable, V, R any](m map[K]V, f func(K) R, opts ...Option) []R {
+	return parallel.MapKeys(m, f, sequential(opts)...)
+}
+
+//MapValues is [parallel.MapValues] that calls f for one value of m at a time
+func MapValues[K comparable, V, R any](m map[K]V, f func(V) R, opts ...Option) []R {
+	return parallel.MapValues(m, f, sequential(opts)...)
+}
+
+//MapStream is [parallel.MapStream] that calls f for the elements of slice in order
+func MapStream[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) <-chan parallel.Result[R] {
+	return parallel.MapStream(ctx, slice, f, sequential(opts)...)
+}
+
+//MapOrdered is [parallel.MapOrdered] that calls f for the elements of slice in order
+func MapOrdered[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) parallel.Ordered[R] {
+	return parallel.MapOrdered(ctx, slice, f, sequential(opts)...)
+}
+
+//MapUnordered is [parallel.MapUnordered] that calls f for the elements of slice in order
+func MapUnordered[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) parallel.Unordered[R] {
+	return parallel.MapUnordered(ctx, slice, f, sequential(opts)...)
+}
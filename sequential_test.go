@@ -0,0 +1,89 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithSequentialFor(t *testing.T) {
+	var mu sync.Mutex
+	var order []int
+	var running int32
+	err := parallel.ForErr(context.Background(), 0, 100, func(i int) error {
+		if atomic.AddInt32(&running, 1) != 1 {
+			t.Error("concurrent", i)
+		}
+		defer atomic.AddInt32(&running, -1)
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+		return nil
+	}, parallel.WithWorkers(8), parallel.WithSequential())
+
+	if err != nil {
+		t.Error(err)
+	}
+	for i, v := range order {
+		if i != v {
+			t.Fatal(order)
+		}
+	}
+	if len(order) != 100 {
+		t.Error(len(order))
+	}
+}
+
+func TestWithSequentialMapStream(t *testing.T) {
+	var running int32
+	var got []int
+	for r := range parallel.MapStream(context.Background(), []int{1, 2, 3, 4}, func(v int) (int, error) {
+		if atomic.AddInt32(&running, 1) != 1 {
+			t.Error("concurrent", v)
+		}
+		defer atomic.AddInt32(&running, -1)
+		return v * 2, nil
+	}, parallel.WithSequential()) {
+		got = append(got, r.Value)
+	}
+	if !slices.Equal(got, []int{2, 4, 6, 8}) {
+		t.Error(got)
+	}
+}
+
+func TestWithSequentialRace(t *testing.T) {
+	var calls []int
+	errFail := errors.New("fail")
+	err := parallel.RaceWithOptions(context.Background(), []parallel.TaskFunc{
+		func() { calls = append(calls, 0); panic(errFail) },
+		func() { calls = append(calls, 1) },
+		func() { calls = append(calls, 2) },
+	}, parallel.WithSequential())
+
+	if err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(calls, []int{0, 1}) {
+		t.Error(calls)
+	}
+}
+
+func TestWithSequentialSeq(t *testing.T) {
+	var got []int
+	parallel.ForEachSeqWithContext(context.Background(), slices.Values([]int{1, 2, 3}), 4, func(v int) {
+		got = append(got, v)
+	}, parallel.WithSequential())
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Error(got)
+	}
+
+	o := parallel.EffectiveOptions(parallel.WithSequential())
+	if !o.Sequential || o.Workers != 1 {
+		t.Error(o)
+	}
+}
@@ -0,0 +1,99 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//PhaseError is an error of a phase of [Series], [Phases] and [SeriesWithOptions]
+type PhaseError struct {
+	//Phase is the index of the phase
+	Phase int
+
+	//Err is the error of the tasks of the phase
+	Err error
+}
+
+func (e *PhaseError) Error() string {
+	return fmt.Sprintf("phase %d: %v", e.Phase, e.Err)
+}
+
+func (e *PhaseError) Unwrap() error {
+	return e.Err
+}
+
+//WithContinueOnError makes [SeriesWithOptions] run the later phases even if a phase failed.
+//the errors of all failed phases are joined
+func WithContinueOnError() Option {
+	return func(c *config) {
+		c.continueOnError = true
+	}
+}
+
+//Series runs the groups one after another.
+//the functions of a group are executed in parallel as [All],
+//and the next group starts when all functions of the group are finished.
+//if a function of a group panics, the later groups do not run
+//
+// err := parallel.Series(
+// 		[]parallel.TaskFunc{download1, download2, download3},
+// 		[]parallel.TaskFunc{merge},
+// 		[]parallel.TaskFunc{upload1, upload2},
+// )
+func Series(groups ...[]TaskFunc) error {
+	return SeriesWithOptions(emptyContext, groups)
+}
+
+//Phases is [Series] with a context.
+//each phase is a barrier: the next phase starts only when all tasks of the phase succeeded.
+//the error of a failed phase is a *PhaseError.
+//use [SeriesWithOptions] to configure the phases
+//
+// err := parallel.Phases(ctx,
+// 		[]parallel.TaskFunc{migrateUsers, migrateOrders},
+// 		[]parallel.TaskFunc{switchTraffic},
+// )
+// var pe *parallel.PhaseError
+// if errors.As(err, &pe) {
+// 		fmt.Println("failed in phase", pe.Phase)
+// }
+func Phases(ctx context.Context, phases ...[]TaskFunc) error {
+	return SeriesWithOptions(ctx, phases)
+}
+
+//SeriesWithOptions is [Series] with a context and options.
+//each group is a phase that runs as [AllWithOptions] configured by opts,
+//and is a barrier: the next phase starts only when all tasks of the phase succeeded,
+//unless [WithContinueOnError] is set.
+//the error of a failed phase is a *PhaseError.
+//with [WithContinueOnError], the *PhaseError of each failed phase are joined
+//if more than one phase failed.
+//when ctx is done, the later groups do not run and it returns ctx.Err()
+//
+// err := parallel.SeriesWithOptions(ctx, [][]parallel.TaskFunc{
+// 		{migrateUsers, migrateOrders},
+// 		{switchTraffic},
+// }, parallel.WithWorkers(4))
+func SeriesWithOptions(ctx context.Context, groups [][]TaskFunc, opts ...Option) error {
+	cfg := newConfig(opts)
+	var errs []error
+	for i, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := AllWithOptions(ctx, group, opts...); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			errs = append(errs, &PhaseError{Phase: i, Err: err})
+			if !cfg.continueOnError {
+				break
+			}
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
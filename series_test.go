@@ -0,0 +1,108 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestSeries(t *testing.T) {
+	var phase1, phase2 int32
+	err := parallel.Series(
+		[]parallel.TaskFunc{
+			func() {
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&phase1, 1)
+			},
+			func() { atomic.AddInt32(&phase1, 1) },
+		},
+		[]parallel.TaskFunc{
+			func() {
+				if atomic.LoadInt32(&phase1) != 2 {
+					t.Error("phase 1 must be finished")
+				}
+				atomic.AddInt32(&phase2, 1)
+			},
+		},
+	)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if phase2 != 1 {
+		t.Error("require phase 2")
+	}
+}
+
+func TestSeriesFail(t *testing.T) {
+	err := parallel.Series(
+		[]parallel.TaskFunc{func() { panic("fail") }},
+		[]parallel.TaskFunc{func() { t.Error("later phase must not run") }},
+	)
+
+	if err == nil {
+		t.Error("require error")
+	}
+}
+
+func TestSeriesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := parallel.SeriesWithOptions(ctx, [][]parallel.TaskFunc{
+		{func() { cancel() }},
+		{func() { t.Error("later phase must not run") }},
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("require canceled", err)
+	}
+}
+
+func TestSeriesPhaseError(t *testing.T) {
+	e := errors.New("fail")
+	err := parallel.SeriesWithOptions(context.Background(), [][]parallel.TaskFunc{
+		{func() {}},
+		{func() { panic(e) }},
+		{func() { t.Error("later phase must not run") }},
+	}, parallel.WithWorkers(1))
+
+	var pe *parallel.PhaseError
+	if !errors.As(err, &pe) || pe.Phase != 1 {
+		t.Error("require error of phase 1", err)
+	}
+}
+
+func TestPhases(t *testing.T) {
+	e := errors.New("fail")
+	err := parallel.Phases(context.Background(),
+		[]parallel.TaskFunc{func() {}},
+		[]parallel.TaskFunc{func() { panic(e) }},
+		[]parallel.TaskFunc{func() { t.Error("later phase must not run") }},
+	)
+
+	//a single failed phase is not joined
+	pe, ok := err.(*parallel.PhaseError)
+	if !ok || pe.Phase != 1 {
+		t.Error("require *PhaseError of phase 1", err)
+	}
+}
+
+func TestSeriesContinueOnError(t *testing.T) {
+	var ran int32
+	err := parallel.SeriesWithOptions(context.Background(), [][]parallel.TaskFunc{
+		{func() { panic("a") }},
+		{func() { atomic.AddInt32(&ran, 1) }},
+		{func() { panic("b") }},
+	}, parallel.WithContinueOnError())
+
+	if ran != 1 {
+		t.Error("require the later phase")
+	}
+	var pe *parallel.PhaseError
+	if !errors.As(err, &pe) || pe.Phase != 0 || err.Error() == pe.Error() {
+		t.Error("require the errors of phase 0 and 2", err)
+	}
+}
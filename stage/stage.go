@@ -0,0 +1,233 @@
+//Package stage provides ready-made stages of a channel pipeline.
+//the items of a pipeline are [parallel.Result] values,
+//so an item that failed in a stage flows through the next stages with its error
+//and the Index of the source element.
+//
+// src := stage.Source(ctx, lines)
+// users := stage.Decode[User](4)(ctx, src)
+// active := stage.Filter(func(u User) bool { return u.Active })(ctx, users)
+// batches := stage.Batch[User](100)(ctx, active)
+// for r := range stage.Retry(4, parallel.Exponential(time.Second, time.Minute), save)(ctx, batches) {
+// 		fmt.Println(r.Index, r.Err)
+// }
+package stage
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+//Stage reads the items of in and sends its items to the returned channel.
+//the returned channel is closed after in is closed or ctx is done
+type Stage[In, Out any] func(ctx context.Context, in <-chan parallel.Result[In]) <-chan parallel.Result[Out]
+
+//Then returns the stage that sends the items of a to b
+func Then[A, B, C any](a Stage[A, B], b Stage[B, C]) Stage[A, C] {
+	return func(ctx context.Context, in <-chan parallel.Result[A]) <-chan parallel.Result[C] {
+		return b(ctx, a(ctx, in))
+	}
+}
+
+//Source sends each element of items with its index.
+//the channel is closed after all elements are sent or ctx is done
+func Source[T any](ctx context.Context, items []T) <-chan parallel.Result[T] {
+	out := make(chan parallel.Result[T])
+	go func() {
+		defer close(out)
+		for i, e := range items {
+			if !send(ctx, out, parallel.Result[T]{Index: i, Value: e}) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+//Map calls f for the value of each item with workers goroutines.
+//items are sent in the order they finish.
+//if f panics, the error of the item is a *parallel.PanicError
+func Map[In, Out any](workers int, f func(ctx context.Context, v In) (Out, error)) Stage[In, Out] {
+	if workers <= 0 {
+		panic("workers must be greater than 0")
+	}
+	return func(ctx context.Context, in <-chan parallel.Result[In]) <-chan parallel.Result[Out] {
+		out := make(chan parallel.Result[Out])
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for r := range receive(ctx, in) {
+					o := parallel.Result[Out]{Index: r.Index, Err: r.Err}
+					if r.Err == nil {
+						o.Value, o.Err = call(ctx, f, r.Value)
+					}
+					if !send(ctx, out, o) {
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+		return out
+	}
+}
+
+//Decode unmarshals each item as JSON into a T with workers goroutines
+func Decode[T any](workers int) Stage[[]byte, T] {
+	return Map(workers, func(ctx context.Context, b []byte) (T, error) {
+		var v T
+		err := json.Unmarshal(b, &v)
+		return v, err
+	})
+}
+
+//Filter sends only the items whose value keep returns true for.
+//items with an error are always sent
+func Filter[T any](keep func(v T) bool) Stage[T, T] {
+	return func(ctx context.Context, in <-chan parallel.Result[T]) <-chan parallel.Result[T] {
+		out := make(chan parallel.Result[T])
+		go func() {
+			defer close(out)
+			for r := range receive(ctx, in) {
+				if r.Err == nil && !keep(r.Value) {
+					continue
+				}
+				if !send(ctx, out, r) {
+					return
+				}
+			}
+		}()
+		return out
+	}
+}
+
+//Batch groups the values of up to n items into a slice.
+//the Index of a batch is the Index of its first item.
+//the last batch may hold fewer than n values.
+//items with an error are sent alone, with a nil value, before the batch being filled
+func Batch[T any](n int) Stage[T, []T] {
+	if n <= 0 {
+		panic("batch size must be greater than 0")
+	}
+	return func(ctx context.Context, in <-chan parallel.Result[T]) <-chan parallel.Result[[]T] {
+		out := make(chan parallel.Result[[]T])
+		go func() {
+			defer close(out)
+			var batch parallel.Result[[]T]
+			for r := range receive(ctx, in) {
+				if r.Err != nil {
+					if !send(ctx, out, parallel.Result[[]T]{Index: r.Index, Err: r.Err}) {
+						return
+					}
+					continue
+				}
+				if len(batch.Value) == 0 {
+					batch = parallel.Result[[]T]{Index: r.Index, Value: make([]T, 0, n)}
+				}
+				batch.Value = append(batch.Value, r.Value)
+				if len(batch.Value) == n {
+					if !send(ctx, out, batch) {
+						return
+					}
+					batch = parallel.Result[[]T]{}
+				}
+			}
+			if len(batch.Value) > 0 {
+				send(ctx, out, batch)
+			}
+		}()
+		return out
+	}
+}
+
+//RateLimit sends each item after l.Wait returns.
+//a *parallel.TokenBucket shared by several stages caps them together
+func RateLimit[T any](l parallel.Limiter) Stage[T, T] {
+	return func(ctx context.Context, in <-chan parallel.Result[T]) <-chan parallel.Result[T] {
+		out := make(chan parallel.Result[T])
+		go func() {
+			defer close(out)
+			for r := range receive(ctx, in) {
+				if err := l.Wait(ctx); err != nil {
+					return
+				}
+				if !send(ctx, out, r) {
+					return
+				}
+			}
+		}()
+		return out
+	}
+}
+
+//Retry is [Map] that calls f again after a failure with the delay of policy,
+//until it succeeds, policy gives up or ctx is done.
+//the error of an item that gave up is the last error of f
+func Retry[In, Out any](workers int, policy parallel.Backoff, f func(ctx context.Context, v In) (Out, error)) Stage[In, Out] {
+	return Map(workers, func(ctx context.Context, v In) (Out, error) {
+		start := time.Now()
+		for attempt := 1; ; attempt++ {
+			o, err := call(ctx, f, v)
+			if err == nil {
+				return o, nil
+			}
+			delay, ok := policy.Next(attempt, time.Since(start))
+			if !ok {
+				return o, err
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return o, err
+			}
+		}
+	})
+}
+
+//call calls f and returns a *parallel.PanicError if f panics
+func call[In, Out any](ctx context.Context, f func(ctx context.Context, v In) (Out, error), v In) (o Out, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &parallel.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f(ctx, v)
+}
+
+//send sends r to out unless ctx is done first
+func send[T any](ctx context.Context, out chan<- T, r T) bool {
+	select {
+	case out <- r:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+//receive returns the items of in until in is closed or ctx is done
+func receive[T any](ctx context.Context, in <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case r, ok := <-in:
+				if !ok || !yield(r) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
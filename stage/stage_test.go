@@ -0,0 +1,131 @@
+package stage_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+	"github.com/rudty/go-parallel/stage"
+)
+
+type user struct {
+	Name   string
+	Active bool
+}
+
+func collect[T any](c <-chan parallel.Result[T]) []parallel.Result[T] {
+	var rs []parallel.Result[T]
+	for r := range c {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Index < rs[j].Index
+	})
+	return rs
+}
+
+func TestDecodeFilterBatch(t *testing.T) {
+	ctx := context.Background()
+	lines := [][]byte{
+		[]byte(`{"Name":"a","Active":true}`),
+		[]byte(`{"Name":"b"}`),
+		[]byte(`not json`),
+		[]byte(`{"Name":"c","Active":true}`),
+		[]byte(`{"Name":"d","Active":true}`),
+	}
+	users := stage.Then(stage.Decode[user](3), stage.Filter(func(u user) bool {
+		return u.Active
+	}))
+	rs := collect(stage.Batch[user](2)(ctx, users(ctx, stage.Source(ctx, lines))))
+
+	var errs, names int
+	for _, r := range rs {
+		if r.Err != nil {
+			if r.Index != 2 {
+				t.Error(r)
+			}
+			errs++
+			continue
+		}
+		if len(r.Value) == 0 || len(r.Value) > 2 {
+			t.Error(r)
+		}
+		for _, u := range r.Value {
+			if !u.Active {
+				t.Error(u)
+			}
+			names++
+		}
+	}
+	if errs != 1 || names != 3 {
+		t.Error(errs, names)
+	}
+}
+
+func TestMapPanic(t *testing.T) {
+	ctx := context.Background()
+	rs := collect(stage.Map(2, func(ctx context.Context, v int) (int, error) {
+		if v == 1 {
+			panic("one")
+		}
+		return v * 10, nil
+	})(ctx, stage.Source(ctx, []int{0, 1, 2})))
+
+	if len(rs) != 3 || rs[0].Value != 0 || rs[2].Value != 20 {
+		t.Fatal(rs)
+	}
+	var p *parallel.PanicError
+	if !errors.As(rs[1].Err, &p) {
+		t.Error(rs[1].Err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	errFlaky := errors.New("flaky")
+	rs := collect(stage.Retry(2, parallel.Constant(time.Millisecond), func(ctx context.Context, v int) (int, error) {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			return 0, errFlaky
+		}
+		return v, nil
+	})(ctx, stage.Source(ctx, []int{1, 2, 3})))
+
+	for i, r := range rs {
+		if r.Err != nil || r.Value != i+1 {
+			t.Error(r)
+		}
+	}
+
+	rs = collect(stage.Retry(1, parallel.WithMaxElapsed(parallel.Constant(time.Millisecond), 5*time.Millisecond), func(ctx context.Context, v int) (int, error) {
+		return 0, errFlaky
+	})(ctx, stage.Source(ctx, []int{1})))
+	if len(rs) != 1 || !errors.Is(rs[0].Err, errFlaky) {
+		t.Error(rs)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	rs := collect(stage.RateLimit[int](parallel.NewTokenBucket(1, 10*time.Millisecond))(ctx, stage.Source(ctx, make([]int, 4))))
+	if len(rs) != 4 {
+		t.Fatal(rs)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Error(elapsed)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := stage.Filter(func(v int) bool { return true })(ctx, stage.Source(ctx, make([]int, 100)))
+	<-out
+	cancel()
+	for range out {
+	}
+}
@@ -0,0 +1,136 @@
+package parallel
+
+import (
+	"context"
+	"sort"
+)
+
+//Result is a result of [MapStream]
+type Result[R any] struct {
+	//Index is the index of the input element
+	Index int
+
+	//Value is the value returned by the function
+	Value R
+
+	//Err is the error returned by the function.
+	//if the function panics, it is a *PanicError
+	Err error
+}
+
+//WithOrderedResults makes [MapStream] send results in the order of the input.
+//results that finish early are held until all results before them are sent
+func WithOrderedResults() Option {
+	return func(c *config) {
+		c.orderedResults = true
+	}
+}
+
+//MapStream calls f for each element of slice in parallel
+//and sends each result as soon as it is finished.
+//the calls run as the iterations of [ForWithOptions] configured by opts,
+//so options such as [WithWorkers] and [WithRateLimit] limit them.
+//the channel is closed when all results are sent or ctx is done.
+//elements that are not called, for example when [WithBudget] denies them, have no result.
+//the caller must read the channel until it is closed or cancel ctx
+//
+// for r := range parallel.MapStream(ctx, urls, fetch) {
+// 		fmt.Println(r.Index, r.Value, r.Err)
+// }
+func MapStream[T, R any](ctx context.Context, slice []T, f func(T) (R, error), opts ...Option) <-chan Result[R] {
+	cfg := newConfig(opts)
+	ordered := cfg.orderedResults
+	out := make(chan Result[R])
+
+	//buffered so that finished functions never wait for the reader
+	results := make(chan Result[R], len(slice))
+	call := func(i int) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r := Result[R]{Index: i}
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.Err = newPanicError(rec)
+			}
+			results <- r
+		}()
+
+		r.Value, r.Err = f(slice[i])
+		return r.Err
+	}
+	go func() {
+		forErrRange(ctx, len(slice), call, cfg)
+		//the loop returns before its iterations only when ctx is done,
+		//and then the reader does not wait for results
+		if ctx.Err() == nil {
+			close(results)
+		}
+	}()
+
+	go func() {
+		defer close(out)
+
+		send := func(r Result[R]) bool {
+			select {
+			case out <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		pending := make(map[int]Result[R])
+		next := 0
+		for {
+			var r Result[R]
+			select {
+			case received, ok := <-results:
+				if !ok {
+					//the results after an element that was not called
+					for _, i := range sortedIndexes(pending) {
+						if !send(pending[i]) {
+							return
+						}
+					}
+					return
+				}
+				r = received
+			case <-ctx.Done():
+				return
+			}
+
+			if !ordered {
+				if !send(r) {
+					return
+				}
+				continue
+			}
+
+			pending[r.Index] = r
+			for {
+				p, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				if !send(p) {
+					return
+				}
+				next++
+			}
+		}
+	}()
+
+	return out
+}
+
+//sortedIndexes returns the indexes of the results in m in ascending order
+func sortedIndexes[R any](m map[int]Result[R]) []int {
+	indexes := make([]int, 0, len(m))
+	for i := range m {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -0,0 +1,123 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestMapStream(t *testing.T) {
+	errOdd := errors.New("odd")
+	s := []int{1, 2, 3, 4, 5}
+	seen := make([]bool, len(s))
+	for r := range parallel.MapStream(context.Background(), s, func(e int) (int, error) {
+		if e%2 == 1 {
+			return 0, errOdd
+		}
+		return e * 10, nil
+	}) {
+		seen[r.Index] = true
+		if s[r.Index]%2 == 1 && r.Err != errOdd {
+			t.Error("require odd error", r)
+		}
+		if s[r.Index]%2 == 0 && r.Value != s[r.Index]*10 {
+			t.Error("require", s[r.Index]*10, r)
+		}
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			t.Error("missing result", i)
+		}
+	}
+}
+
+func TestMapStreamOrdered(t *testing.T) {
+	s := []int{50, 40, 30, 20, 10}
+	next := 0
+	for r := range parallel.MapStream(context.Background(), s, func(e int) (int, error) {
+		time.Sleep(time.Duration(e) * time.Millisecond)
+		return e, nil
+	}, parallel.WithOrderedResults()) {
+		if r.Index != next || r.Value != s[next] {
+			t.Error("require in order", next, r)
+		}
+		next++
+	}
+
+	if next != len(s) {
+		t.Error("require all results", next)
+	}
+}
+
+func TestMapStreamPanic(t *testing.T) {
+	for r := range parallel.MapStream(context.Background(), []int{1}, func(e int) (int, error) {
+		panic("hello")
+	}) {
+		var p *parallel.PanicError
+		if !errors.As(r.Err, &p) {
+			t.Error("require panic error", r.Err)
+		}
+	}
+}
+
+func TestMapStreamContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	results := parallel.MapStream(ctx, []int{1, 2, 3}, func(e int) (int, error) {
+		return e, nil
+	})
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("channel must be closed after cancel")
+		}
+	}
+}
+
+func TestMapStreamWorkers(t *testing.T) {
+	var running, most int32
+	s := make([]int, 20)
+	n := 0
+	for range parallel.MapStream(context.Background(), s, func(e int) (int, error) {
+		if r := atomic.AddInt32(&running, 1); r > atomic.LoadInt32(&most) {
+			atomic.StoreInt32(&most, r)
+		}
+		defer atomic.AddInt32(&running, -1)
+		time.Sleep(time.Millisecond)
+		return e, nil
+	}, parallel.WithWorkers(2)) {
+		n++
+	}
+
+	if n != len(s) {
+		t.Error("require all results", n)
+	}
+	if most > 2 {
+		t.Error("require at most 2 calls at a time", most)
+	}
+}
+
+func TestMapStreamBudget(t *testing.T) {
+	b := parallel.NewBudget(3, time.Hour, nil)
+	var indexes []int
+	for r := range parallel.MapStream(context.Background(), []int{1, 2, 3, 4, 5}, func(e int) (int, error) {
+		return e, nil
+	}, parallel.WithBudget(b, nil), parallel.WithOrderedResults(), parallel.WithSequential()) {
+		indexes = append(indexes, r.Index)
+	}
+
+	if len(indexes) != 3 || indexes[0] != 0 || indexes[2] != 2 {
+		t.Error("require the results of the calls the budget allowed", indexes)
+	}
+}
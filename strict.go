@@ -0,0 +1,30 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+)
+
+//WithDescending makes the loop count down from begin to end.
+//begin is included and end is not
+//
+// parallel.ForWithOptions(ctx, 5, 0, f, parallel.WithDescending()) // 5 4 3 2 1
+func WithDescending() Option {
+	return func(c *config) {
+		c.descending = true
+	}
+}
+
+//ForStrict is [ForWithOptions] that returns ErrInvalidRange
+//instead of doing nothing when the range is reversed.
+//with [WithDescending], begin must not be less than end
+//an empty range is valid
+func ForStrict(c context.Context, begin int, end int, f ForLoop, opts ...Option) error {
+	cfg := newConfig(opts)
+	if (!cfg.descending && begin > end) || (cfg.descending && begin < end) {
+		return fmt.Errorf("%w: begin %d, end %d", ErrInvalidRange, begin, end)
+	}
+
+	forWithConfig(c, begin, end, f, cfg)
+	return nil
+}
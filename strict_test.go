@@ -0,0 +1,49 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForStrictReversed(t *testing.T) {
+	err := parallel.ForStrict(context.Background(), 5, 3, func(i int) {
+		t.Error("reversed range must not be called", i)
+	})
+	if !errors.Is(err, parallel.ErrInvalidRange) {
+		t.Error("require invalid range", err)
+	}
+
+	err = parallel.ForStrict(context.Background(), 3, 5, func(i int) {
+		t.Error("reversed range must not be called", i)
+	}, parallel.WithDescending())
+	if !errors.Is(err, parallel.ErrInvalidRange) {
+		t.Error("require invalid range", err)
+	}
+}
+
+func TestForStrictEmpty(t *testing.T) {
+	if err := parallel.ForStrict(context.Background(), 3, 3, func(i int) {}); err != nil {
+		t.Error("empty range is valid", err)
+	}
+}
+
+func TestForDescending(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	err := parallel.ForStrict(context.Background(), 5, 0, func(i int) {
+		mu.Lock()
+		seen[i] = true
+		mu.Unlock()
+	}, parallel.WithDescending())
+
+	if err != nil {
+		t.Error(err)
+	}
+	if len(seen) != 5 || !seen[5] || seen[0] {
+		t.Error("require 5 4 3 2 1", seen)
+	}
+}
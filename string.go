@@ -0,0 +1,49 @@
+package parallel
+
+import (
+	"context"
+)
+
+//StringLoop type is used in the ForEachString function
+//i is the byte index of r in the string
+type StringLoop func(i int, r rune)
+
+//WithBytes makes [ForEachString] iterate the bytes of the string instead of the runes.
+//each byte is passed as a rune
+func WithBytes() Option {
+	return func(c *config) {
+		c.bytes = true
+	}
+}
+
+//ForEachString loops the runes of s in parallel
+//the index is the same as range
+//
+// parallel.ForEachString("hello", func(i int, r rune) {
+// 		fmt.Println(i, string(r))
+// })
+func ForEachString(s string, f StringLoop, opts ...Option) {
+	ForEachStringWithContext(emptyContext, s, f, opts...)
+}
+
+//ForEachStringWithContext loops the runes of s in parallel
+//the index is the same as range
+func ForEachStringWithContext(ctx context.Context, s string, f StringLoop, opts ...Option) {
+	cfg := newConfig(opts)
+	if cfg.bytes {
+		forRange(ctx, len(s), func(i int) {
+			f(i, rune(s[i]))
+		}, cfg)
+		return
+	}
+
+	indices := make([]int, 0, len(s))
+	runes := make([]rune, 0, len(s))
+	for i, r := range s {
+		indices = append(indices, i)
+		runes = append(runes, r)
+	}
+	forRange(ctx, len(runes), func(i int) {
+		f(indices[i], runes[i])
+	}, cfg)
+}
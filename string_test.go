@@ -0,0 +1,40 @@
+package parallel_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForEachStringRunes(t *testing.T) {
+	s := "a가b"
+	m := sync.Map{}
+	parallel.ForEachString(s, func(i int, r rune) {
+		m.Store(i, r)
+	})
+
+	for i, r := range s {
+		if v, _ := m.Load(i); v != r {
+			t.Error("require", i, r, v)
+		}
+	}
+}
+
+func TestForEachStringBytes(t *testing.T) {
+	s := "a가b"
+	var mu sync.Mutex
+	count := 0
+	parallel.ForEachString(s, func(i int, r rune) {
+		if r != rune(s[i]) {
+			t.Error("require byte", i, s[i], r)
+		}
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}, parallel.WithBytes())
+
+	if count != len(s) {
+		t.Error("require", len(s), count)
+	}
+}
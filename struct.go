@@ -0,0 +1,101 @@
+//go:build !parallel_noreflect
+
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+//ForEachStruct loops the exported fields of the struct in parallel
+//s: struct, pointer to struct
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+//
+// type config struct {
+// 		Name string
+// 		Port int
+// }
+// parallel.ForEachStruct(config{"a", 80}, func(name string, value interface{}) {
+// 		fmt.Println(name, value)
+// })
+func ForEachStruct(s interface{}, f interface{}) {
+	ForEachStructWithContext(emptyContext, s, f)
+}
+
+//ForEachStructWithContext loops the exported fields of the struct in parallel
+//s: struct, pointer to struct
+//f: any function. if its first parameter is a context.Context, it receives the context of the iteration
+func ForEachStructWithContext(ctx context.Context, s interface{}, f interface{}) {
+	forEachStructWithConfig(ctx, s, f, &config{})
+}
+
+//ForEachStructWithOptions is [ForEachStructWithContext] configured by opts
+func ForEachStructWithOptions(ctx context.Context, s interface{}, f interface{}, opts ...Option) {
+	forEachStructWithConfig(ctx, s, f, newConfig(opts))
+}
+
+func forEachStructWithConfig(ctx context.Context, s interface{}, f interface{}, cfg *config) {
+	reflectionStruct := reflect.Indirect(reflect.ValueOf(s))
+	if reflectionStruct.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("%v is not a struct", reflectionStruct.Type()))
+	}
+
+	structType := reflectionStruct.Type()
+	var fields []reflect.StructField
+	for i := 0; i < structType.NumField(); i++ {
+		if field := structType.Field(i); field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+
+	ctx = withRunID(ctx)
+	reflectionFunc := newReflectFunc(ctx, f, len(fields), cfg)
+	funcType := reflectionFunc
+	funcArgc := funcType.NumIn()
+
+	if funcArgc >= 1 && !reflect.TypeOf("").AssignableTo(funcType.In(0)) {
+		//reflect.TypeOf("") = string type
+		panic("first argument is not a string")
+	}
+
+	if funcArgc == 2 {
+		/**
+		* for name, value := range fields {
+		*	f(name, value)
+		* }
+		**/
+		argType := funcType.In(1)
+		for _, field := range fields {
+			if !field.Type.AssignableTo(argType) {
+				panic(fmt.Sprintf("field %v type: %v but func second arg type: %v", field.Name, field.Type, argType))
+			}
+		}
+
+		forRange(ctx, len(fields), func(i int) {
+			field := fields[i]
+			reflectionFunc.Call(i, []reflect.Value{
+				reflect.ValueOf(field.Name),
+				reflectionStruct.FieldByIndex(field.Index),
+			})
+		}, cfg)
+	} else if funcArgc == 1 {
+		/**
+		* for name := range fields {
+		*	f(name)
+		* }
+		**/
+		forRange(ctx, len(fields), func(i int) {
+			reflectionFunc.Call(i, []reflect.Value{reflect.ValueOf(fields[i].Name)})
+		}, cfg)
+	} else if funcArgc == 0 {
+		/**
+		* for _ := range fields {
+		*	f()
+		* }
+		**/
+		forRange(ctx, len(fields), func(i int) {
+			reflectionFunc.Call(i, emptyIn)
+		}, cfg)
+	}
+}
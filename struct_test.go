@@ -0,0 +1,58 @@
+//go:build !parallel_noreflect
+
+package parallel_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+type structTestConfig struct {
+	Name    string
+	Port    int
+	private int
+}
+
+func TestForEachStruct(t *testing.T) {
+	m := sync.Map{}
+	parallel.ForEach(structTestConfig{"a", 80, 1}, func(name string, value interface{}) {
+		m.Store(name, value)
+	})
+
+	if v, _ := m.Load("Name"); v != "a" {
+		t.Error("require a", v)
+	}
+	if v, _ := m.Load("Port"); v != 80 {
+		t.Error("require 80", v)
+	}
+	if _, ok := m.Load("private"); ok {
+		t.Error("unexported field must be skipped")
+	}
+}
+
+func TestForEachStructPointer(t *testing.T) {
+	m := sync.Map{}
+	parallel.ForEach(&structTestConfig{"a", 80, 1}, func(name string) {
+		m.Store(name, true)
+	})
+
+	count := 0
+	m.Range(func(k, v interface{}) bool {
+		count++
+		return true
+	})
+	if count != 2 {
+		t.Error("require 2 fields", count)
+	}
+}
+
+func TestForEachStructError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Port is not a string")
+		}
+	}()
+	parallel.ForEachStruct(structTestConfig{}, func(name string, value string) {})
+}
@@ -0,0 +1,101 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//TaskTiming is the time a task of [AllWithOptions] or [RaceWithOptions] ran
+type TaskTiming struct {
+	//Start is when the task started. it is zero if the task did not start
+	Start time.Time
+
+	//End is when the task finished, including retries.
+	//it is zero if the task did not finish before the call returned
+	End time.Time
+}
+
+//Duration returns End - Start.
+//if the task did not finish, it is 0
+func (t TaskTiming) Duration() time.Duration {
+	if t.Start.IsZero() || t.End.IsZero() {
+		return 0
+	}
+	return t.End.Sub(t.Start)
+}
+
+//Timings is filled by [WithTimings] when the call returns
+type Timings struct {
+	//Tasks are the timings of the tasks in the order of the arguments
+	Tasks []TaskTiming
+
+	//Winner is the index of the task that won [RaceWithOptions].
+	//it is -1 if there is no winner or the call is not a race
+	Winner int
+}
+
+//WinnerDuration returns the duration of the winner of the race.
+//if there is no winner, it is 0
+func (t *Timings) WinnerDuration() time.Duration {
+	if t.Winner < 0 || t.Winner >= len(t.Tasks) {
+		return 0
+	}
+	return t.Tasks[t.Winner].Duration()
+}
+
+//WithTimings fills t with the start and end of each task
+//of [AllWithOptions] and [RaceWithOptions] when the call returns
+//
+// var t parallel.Timings
+// parallel.RaceWithOptions(ctx, tasks, parallel.WithTimings(&t))
+// fmt.Println(t.Winner, t.WinnerDuration())
+func WithTimings(t *Timings) Option {
+	return func(c *config) {
+		c.timings = t
+	}
+}
+
+//timingRecorder records the start and end of tasks in unix nanoseconds.
+//tasks that are still running may record after the call returned
+type timingRecorder struct {
+	starts []int64
+	ends   []int64
+}
+
+//newTimingRecorder returns nil if t is nil
+func newTimingRecorder(t *Timings, n int) *timingRecorder {
+	if t == nil {
+		return nil
+	}
+	return &timingRecorder{starts: make([]int64, n), ends: make([]int64, n)}
+}
+
+func (r *timingRecorder) start(i int) {
+	if r != nil {
+		atomic.StoreInt64(&r.starts[i], time.Now().UnixNano())
+	}
+}
+
+func (r *timingRecorder) end(i int) {
+	if r != nil {
+		atomic.StoreInt64(&r.ends[i], time.Now().UnixNano())
+	}
+}
+
+//fill copies the recorded timings to t
+func (r *timingRecorder) fill(t *Timings, winner int) {
+	if r == nil {
+		return
+	}
+
+	t.Tasks = make([]TaskTiming, len(r.starts))
+	t.Winner = winner
+	for i := range r.starts {
+		if start := atomic.LoadInt64(&r.starts[i]); start != 0 {
+			t.Tasks[i].Start = time.Unix(0, start)
+		}
+		if end := atomic.LoadInt64(&r.ends[i]); end != 0 {
+			t.Tasks[i].End = time.Unix(0, end)
+		}
+	}
+}
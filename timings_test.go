@@ -0,0 +1,45 @@
+package parallel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithTimingsAll(t *testing.T) {
+	var timings parallel.Timings
+	parallel.AllWithOptions(context.Background(), []parallel.TaskFunc{
+		func() { time.Sleep(100 * time.Millisecond) },
+		func() {},
+	}, parallel.WithTimings(&timings))
+
+	if len(timings.Tasks) != 2 || timings.Winner != -1 {
+		t.Fatal("bad timings", timings)
+	}
+	if d := timings.Tasks[0].Duration(); d < 100*time.Millisecond {
+		t.Error("require slow task", d)
+	}
+	if timings.Tasks[1].Duration() >= timings.Tasks[0].Duration() {
+		t.Error("require fast task", timings.Tasks)
+	}
+}
+
+func TestWithTimingsRace(t *testing.T) {
+	var timings parallel.Timings
+	parallel.RaceWithOptions(context.Background(), []parallel.TaskFunc{
+		func() { time.Sleep(time.Second) },
+		func() { time.Sleep(50 * time.Millisecond) },
+	}, parallel.WithTimings(&timings))
+
+	if timings.Winner != 1 {
+		t.Error("require winner 1", timings.Winner)
+	}
+	if d := timings.WinnerDuration(); d < 50*time.Millisecond || d > time.Second {
+		t.Error("bad winner duration", d)
+	}
+	if !timings.Tasks[0].End.IsZero() {
+		t.Error("loser must not be finished", timings.Tasks[0])
+	}
+}
@@ -0,0 +1,37 @@
+package parallel
+
+import (
+	"context"
+)
+
+//traceIDKey is the context key of the trace id.
+//it is not exported, use [TraceID] to read the id
+type traceIDKey struct{}
+
+//WithTraceIDs calls gen for each iteration and stores the result in the context of the iteration.
+//read it with [TraceID] to correlate the logs, metrics and errors of a single item
+//
+// parallel.ForContext(ctx, 0, len(orders), func(ctx context.Context, i int) {
+// 		log.Println(parallel.TraceID(ctx), "processing")
+// }, parallel.WithTraceIDs(func(i int) string {
+// 		return orders[i].ID
+// }))
+func WithTraceIDs(gen func(i int) string) Option {
+	return func(c *config) {
+		c.traceIDs = gen
+	}
+}
+
+//TraceID returns the trace id carried by ctx.
+//if ctx has no trace id, it returns ""
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+//ContextWithTraceID returns a copy of ctx that carries id.
+//use it to propagate the trace id of an iteration into nested calls
+//that do not receive the context of the iteration
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
@@ -0,0 +1,30 @@
+package parallel_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWithTraceIDs(t *testing.T) {
+	parallel.ForContext(context.Background(), 0, 10, func(ctx context.Context, i int) {
+		if id := parallel.TraceID(ctx); id != "item-"+strconv.Itoa(i) {
+			t.Error("bad trace id", i, id)
+		}
+	}, parallel.WithTraceIDs(func(i int) string {
+		return "item-" + strconv.Itoa(i)
+	}))
+}
+
+func TestTraceIDEmpty(t *testing.T) {
+	if id := parallel.TraceID(context.Background()); id != "" {
+		t.Error("require empty", id)
+	}
+
+	ctx := parallel.ContextWithTraceID(context.Background(), "abc")
+	if id := parallel.TraceID(ctx); id != "abc" {
+		t.Error("require abc", id)
+	}
+}
@@ -0,0 +1,170 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+//WithPostOrder makes [WalkTree] visit a node after all of its children were visited.
+//by default a node is visited before its children
+func WithPostOrder() Option {
+	return func(c *config) {
+		c.postOrder = true
+	}
+}
+
+//WalkTree visits root and its descendants in parallel using the workers of [WithWorkers].
+//children returns the children of a node.
+//in pre-order, the default, the children of a node whose visit returned an error are not visited.
+//each node is visited once even if it is reached more than once,
+//so WalkTree also walks graphs with shared nodes and cycles.
+//the errors are aggregated like [ForErr] with the node as the key
+//
+// err := parallel.WalkTree(ctx, "/", func(dir string) []string {
+// 		return subdirs(dir)
+// }, func(dir string) error {
+// 		return index(dir)
+// }, parallel.WithWorkers(8))
+func WalkTree[T comparable](ctx context.Context, root T, children func(T) []T, visit func(T) error, opts ...Option) error {
+	cfg := newConfig(opts)
+	w := &treeWalk[T]{
+		ctx:      ctx,
+		children: children,
+		visit:    visit,
+		post:     cfg.postOrder,
+		seen:     map[T]bool{root: true},
+		queue:    []*treeNode[T]{{value: root}},
+		pending:  1,
+		errs:     newErrorCollector("node", cfg),
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	startWorkers(cfg.workerLimit(), w.work).Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return w.errs.err()
+}
+
+//treeNode is a node waiting to be visited by [WalkTree]
+type treeNode[T comparable] struct {
+	value  T
+	parent *treeNode[T]
+
+	//remaining is the number of the children that are not visited yet in post-order
+	remaining int32
+}
+
+//treeWalk is the state of a call of [WalkTree]
+type treeWalk[T comparable] struct {
+	ctx      context.Context
+	children func(T) []T
+	visit    func(T) error
+	post     bool
+	errs     *errorCollector
+	visits   int64
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []*treeNode[T]
+	seen    map[T]bool
+	pending int
+}
+
+//work processes the queued nodes until no node is left
+func (w *treeWalk[T]) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.pending > 0 {
+			w.cond.Wait()
+		}
+		if w.pending == 0 {
+			w.mu.Unlock()
+			return
+		}
+		n := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.mu.Unlock()
+
+		w.process(n)
+
+		w.mu.Lock()
+		w.pending--
+		if w.pending == 0 {
+			//wake up the workers to return
+			w.cond.Broadcast()
+		}
+		w.mu.Unlock()
+	}
+}
+
+//process visits n and queues its children
+func (w *treeWalk[T]) process(n *treeNode[T]) {
+	if w.ctx.Err() != nil {
+		return
+	}
+	if !w.post {
+		if w.call(n.value) != nil {
+			return
+		}
+		w.push(n, w.childrenOf(n.value))
+		return
+	}
+
+	if w.push(n, w.childrenOf(n.value)) == 0 {
+		w.visitUp(n)
+	}
+}
+
+//visitUp visits n in post-order and then the parents whose children are all visited
+func (w *treeWalk[T]) visitUp(n *treeNode[T]) {
+	for n != nil {
+		w.call(n.value)
+		n = n.parent
+		if n == nil || atomic.AddInt32(&n.remaining, -1) != 0 {
+			return
+		}
+	}
+}
+
+//push queues the children of parent that were not seen and returns the number of them
+func (w *treeWalk[T]) push(parent *treeNode[T], children []T) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	count := 0
+	for _, c := range children {
+		if w.seen[c] {
+			continue
+		}
+		w.seen[c] = true
+		w.queue = append(w.queue, &treeNode[T]{value: c, parent: parent})
+		count++
+	}
+	parent.remaining = int32(count)
+	w.pending += count
+	w.cond.Broadcast()
+	return count
+}
+
+//childrenOf calls children. a panic is treated as no children
+func (w *treeWalk[T]) childrenOf(v T) (children []T) {
+	callRecover(func() {
+		children = w.children(v)
+	})
+	return children
+}
+
+//call visits v and collects the error
+func (w *treeWalk[T]) call(v T) (err error) {
+	index := int(atomic.AddInt64(&w.visits, 1) - 1)
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+		err = w.errs.add(index, v, err)
+	}()
+	return w.visit(v)
+}
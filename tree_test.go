@@ -0,0 +1,105 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+//binaryChildren is a complete binary tree of 15 nodes
+func binaryChildren(n int) []int {
+	if 2*n+2 < 15 {
+		return []int{2*n + 1, 2*n + 2}
+	}
+	return nil
+}
+
+func walkOrder(t *testing.T, opts ...parallel.Option) map[int]int {
+	var mu sync.Mutex
+	order := map[int]int{}
+	err := parallel.WalkTree(context.Background(), 0, binaryChildren, func(n int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := order[n]; ok {
+			t.Error("visited twice", n)
+		}
+		order[n] = len(order)
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 15 {
+		t.Fatal("require 15 nodes", len(order))
+	}
+	return order
+}
+
+func TestWalkTreePreOrder(t *testing.T) {
+	order := walkOrder(t, parallel.WithWorkers(4))
+	for n := 1; n < 15; n++ {
+		if parent := (n - 1) / 2; order[parent] > order[n] {
+			t.Error("child before parent", n)
+		}
+	}
+}
+
+func TestWalkTreePostOrder(t *testing.T) {
+	order := walkOrder(t, parallel.WithWorkers(4), parallel.WithPostOrder())
+	for n := 1; n < 15; n++ {
+		if parent := (n - 1) / 2; order[parent] < order[n] {
+			t.Error("parent before child", n)
+		}
+	}
+}
+
+func TestWalkTreeCycle(t *testing.T) {
+	graph := map[string][]string{"a": {"b"}, "b": {"c", "a"}, "c": {"a", "b"}}
+	for _, opts := range [][]parallel.Option{nil, {parallel.WithPostOrder()}} {
+		var mu sync.Mutex
+		visits := map[string]int{}
+		parallel.WalkTree(context.Background(), "a", func(n string) []string {
+			return graph[n]
+		}, func(n string) error {
+			mu.Lock()
+			visits[n]++
+			mu.Unlock()
+			return nil
+		}, opts...)
+
+		if len(visits) != 3 || visits["a"] != 1 || visits["b"] != 1 || visits["c"] != 1 {
+			t.Error("require each node once", visits)
+		}
+	}
+}
+
+func TestWalkTreeError(t *testing.T) {
+	e := errors.New("skip")
+	var mu sync.Mutex
+	visited := map[int]bool{}
+	err := parallel.WalkTree(context.Background(), 0, binaryChildren, func(n int) error {
+		mu.Lock()
+		visited[n] = true
+		mu.Unlock()
+		if n == 1 {
+			return e
+		}
+		return nil
+	})
+
+	if !errors.Is(err, e) {
+		t.Error("require error", err)
+	}
+	//the subtree of 1 is 3, 4, 7, 8, 9, 10
+	for _, n := range []int{3, 4, 7, 8, 9, 10} {
+		if visited[n] {
+			t.Error("visited the child of a failed node", n)
+		}
+	}
+	if len(visited) != 9 {
+		t.Error("require 9 nodes", len(visited))
+	}
+}
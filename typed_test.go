@@ -0,0 +1,60 @@
+package parallel_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForEachOf(t *testing.T) {
+	s := []int{5, 4, 3, 2, 1}
+	var sum int64
+	parallel.ForEachOf(s, func(i int, e int) {
+		if s[i] != e {
+			t.Error("require", s[i], e)
+		}
+		atomic.AddInt64(&sum, int64(e))
+	})
+
+	if sum != 15 {
+		t.Error("require 15", sum)
+	}
+}
+
+func TestForEachIndexOf(t *testing.T) {
+	s := []int{5, 4, 3, 2, 1}
+	parallel.ForEachIndexOf(s, func(i int) {
+		s[i] *= 2
+	})
+
+	for i, e := range []int{10, 8, 6, 4, 2} {
+		if s[i] != e {
+			t.Error("require", e, s[i])
+		}
+	}
+}
+
+func TestForEachMapOf(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := sync.Map{}
+	parallel.ForEachMapOfWithOptions(context.Background(), m, func(k string, v int) {
+		result.Store(k, v)
+	}, parallel.WithClaiming())
+
+	for k, v := range m {
+		if r, _ := result.Load(k); r != v {
+			t.Error("require", k, v, r)
+		}
+	}
+}
+
+func BenchmarkForEachOf(b *testing.B) {
+	s := make([]int, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parallel.ForEachOfWithOptions(context.Background(), s, func(i int, e int) {}, parallel.WithClaiming())
+	}
+}
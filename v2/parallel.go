@@ -0,0 +1,115 @@
+//Package parallel is the v2 API of github.com/rudty/go-parallel.
+//every function takes a context, is generic over the element type,
+//returns the errors of the calls and is configured by options.
+//the context passed to each call carries the Meta, trace id and run id of the iteration.
+//
+//the v1 package is unchanged, so For, ForEach, All and Race of v1 keep working
+//and both packages can be used in the same program while code is migrated.
+//the options are shared: Option is an alias of the v1 Option,
+//so every v1 option such as parallel.WithBudget can be passed to the v2 functions.
+//
+//v2 is a package of the v1 module, not a module of its own:
+//it is released with the tags of v1, so the Option of v2 is always the Option of the same v1 release
+//and the import path github.com/rudty/go-parallel/v2 is this directory.
+//it becomes a module when v1 is frozen and the two are versioned separately
+//
+// import parallel "github.com/rudty/go-parallel/v2"
+//
+// err := parallel.ForEach(ctx, urls, func(ctx context.Context, i int, url string) error {
+// 		return fetch(ctx, url)
+// }, parallel.WithWorkers(8))
+package parallel
+
+import (
+	"context"
+
+	v1 "github.com/rudty/go-parallel"
+)
+
+//Option configures a call. it is the Option of v1
+type Option = v1.Option
+
+//For calls f for each index from begin to end in parallel
+//and returns the errors of the calls like v1 ForErr.
+//a panic is returned as a *v1.PanicError
+func For(ctx context.Context, begin int, end int, f func(ctx context.Context, i int) error, opts ...Option) error {
+	return v1.ForErrContext(ctx, begin, end, f, opts...)
+}
+
+//ForEach calls f for each element of slice in parallel
+//and returns the errors of the calls
+func ForEach[T any](ctx context.Context, slice []T, f func(ctx context.Context, i int, v T) error, opts ...Option) error {
+	call := func(ctx context.Context, i int) error {
+		return f(ctx, i, slice[i])
+	}
+	if v1.EffectiveOptions(opts...).Descending {
+		return v1.ForErrContext(ctx, len(slice)-1, -1, call, opts...)
+	}
+	return v1.ForErrContext(ctx, 0, len(slice), call, opts...)
+}
+
+//Map calls f for each element of slice in parallel
+//and returns the results in the order of slice.
+//the result of a call that failed is the zero value
+func Map[T, R any](ctx context.Context, slice []T, f func(ctx context.Context, v T) (R, error), opts ...Option) ([]R, error) {
+	results := make([]R, len(slice))
+	err := ForEach(ctx, slice, func(ctx context.Context, i int, v T) error {
+		r, err := f(ctx, v)
+		if err != nil {
+			return err
+		}
+		results[i] = r
+		return nil
+	}, opts...)
+	return results, err
+}
+
+//All calls functions in parallel and returns their errors
+//when all of them returned or ctx is done
+func All(ctx context.Context, functions []func(ctx context.Context) error, opts ...Option) error {
+	return For(ctx, 0, len(functions), func(ctx context.Context, i int) error {
+		return functions[i](ctx)
+	}, opts...)
+}
+
+//Race calls functions in parallel and returns the value of the first one that succeeds.
+//the context passed to the functions is canceled when the race is over.
+//if all of them failed, the error holds the error of each function
+func Race[T any](ctx context.Context, functions ...func(ctx context.Context) (T, error)) (T, error) {
+	return v1.RaceRetry(ctx, nil, functions...)
+}
+
+//WithWorkers is v1 WithWorkers
+func WithWorkers(n int) Option {
+	return v1.WithWorkers(n)
+}
+
+//WithCollectAllErrors is v1 WithCollectAllErrors
+func WithCollectAllErrors(max int) Option {
+	return v1.WithCollectAllErrors(max)
+}
+
+//WithErrorMapper is v1 WithErrorMapper
+func WithErrorMapper(mapper func(i int, err error) error) Option {
+	return v1.WithErrorMapper(mapper)
+}
+
+//WithIgnoreErrors is v1 WithIgnoreErrors
+func WithIgnoreErrors(matchers ...func(error) bool) Option {
+	return v1.WithIgnoreErrors(matchers...)
+}
+
+//WithLimiter is v1 WithLimiter
+func WithLimiter(l v1.Limiter) Option {
+	return v1.WithLimiter(l)
+}
+
+//WithDescending is v1 WithDescending
+func WithDescending() Option {
+	return v1.WithDescending()
+}
+
+//WithSequential is v1 WithSequential
+func WithSequential() Option {
+	return v1.WithSequential()
+}
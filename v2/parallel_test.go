@@ -0,0 +1,88 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	v1 "github.com/rudty/go-parallel"
+	parallel "github.com/rudty/go-parallel/v2"
+)
+
+func TestFor(t *testing.T) {
+	errBad := errors.New("bad")
+	err := parallel.For(context.Background(), 0, 10, func(ctx context.Context, i int) error {
+		if m, ok := v1.MetaFrom(ctx); !ok || m.Index != i {
+			t.Error(m)
+		}
+		if i == 3 {
+			return errBad
+		}
+		return nil
+	}, parallel.WithWorkers(2))
+
+	if !errors.Is(err, errBad) {
+		t.Error(err)
+	}
+}
+
+func TestForEachDescending(t *testing.T) {
+	var order []string
+	err := parallel.ForEach(context.Background(), []string{"a", "b", "c"}, func(ctx context.Context, i int, v string) error {
+		order = append(order, v)
+		return nil
+	}, parallel.WithSequential(), parallel.WithDescending())
+
+	if err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(order, []string{"c", "b", "a"}) {
+		t.Error(order)
+	}
+}
+
+func TestMap(t *testing.T) {
+	got, err := parallel.Map(context.Background(), []int{1, 2, 3}, func(ctx context.Context, v int) (string, error) {
+		return strconv.Itoa(v * 2), nil
+	})
+	if err != nil || !slices.Equal(got, []string{"2", "4", "6"}) {
+		t.Error(got, err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	err := parallel.All(context.Background(), []func(ctx context.Context) error{
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { panic("fail") },
+	}, parallel.WithCollectAllErrors(0))
+
+	var p *v1.PanicError
+	if !errors.As(err, &p) {
+		t.Error(err)
+	}
+}
+
+func TestRace(t *testing.T) {
+	v, err := parallel.Race(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, errors.New("fail")
+	}, func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 2 {
+		t.Error(v, err)
+	}
+}
+
+func TestV1Options(t *testing.T) {
+	var ids []string
+	err := parallel.For(context.Background(), 0, 2, func(ctx context.Context, i int) error {
+		ids = append(ids, v1.TraceID(ctx))
+		return nil
+	}, parallel.WithSequential(), v1.WithTraceIDs(strconv.Itoa))
+
+	if err != nil || !slices.Equal(ids, []string{"0", "1"}) {
+		t.Error(ids, err)
+	}
+}
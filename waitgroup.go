@@ -0,0 +1,94 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//WaitGroup waits for the functions started by Go.
+//unlike sync.WaitGroup, Wait can be canceled by a context
+//and panics of the functions are returned as errors.
+//the zero value is ready to use
+//
+// var wg parallel.WaitGroup
+// wg.Go(task1)
+// wg.Go(task2)
+// err := wg.Wait(ctx)
+type WaitGroup struct {
+	mu      sync.Mutex
+	running int
+	done    chan struct{}
+	errs    []error
+}
+
+//Go calls f in a new goroutine.
+//if f panics, the panic is returned by Wait as a *PanicError
+func (wg *WaitGroup) Go(f TaskFunc) {
+	wg.mu.Lock()
+	wg.running++
+	wg.mu.Unlock()
+
+	go func() {
+		var err error
+		defer func() {
+			wg.finish(err)
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+		}()
+
+		f()
+	}()
+}
+
+//finish is called when a function started by Go is finished
+func (wg *WaitGroup) finish(err error) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	if err != nil {
+		wg.errs = append(wg.errs, err)
+	}
+	wg.running--
+	if wg.running == 0 && wg.done != nil {
+		close(wg.done)
+		wg.done = nil
+	}
+}
+
+//Wait waits for all functions started by Go to finish
+//and returns the panics of them joined into one error.
+//each panic is returned once, so the WaitGroup can be reused after Wait returns.
+//if ctx is done first, it returns ctx.Err() and the functions keep running
+func (wg *WaitGroup) Wait(ctx context.Context) error {
+	wg.mu.Lock()
+	if wg.running == 0 {
+		defer wg.mu.Unlock()
+		return wg.takeErrs()
+	}
+	if wg.done == nil {
+		wg.done = make(chan struct{})
+	}
+	done := wg.done
+	wg.mu.Unlock()
+
+	select {
+	case <-done:
+		wg.mu.Lock()
+		defer wg.mu.Unlock()
+		return wg.takeErrs()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//takeErrs returns the errors joined into one error and clears them.
+//wg.mu must be held
+func (wg *WaitGroup) takeErrs() error {
+	err := errors.Join(wg.errs...)
+	wg.errs = nil
+	return err
+}
@@ -0,0 +1,78 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWaitGroup(t *testing.T) {
+	var wg parallel.WaitGroup
+	var count int32
+	for i := 0; i < 10; i++ {
+		wg.Go(func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&count, 1)
+		})
+	}
+
+	if err := wg.Wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if count != 10 {
+		t.Error("require 10", count)
+	}
+}
+
+func TestWaitGroupPanic(t *testing.T) {
+	var wg parallel.WaitGroup
+	wg.Go(func() {})
+	wg.Go(func() {
+		panic("hello")
+	})
+
+	var p *parallel.PanicError
+	if err := wg.Wait(context.Background()); !errors.As(err, &p) || p.Value != "hello" {
+		t.Error("require panic error", err)
+	}
+}
+
+func TestWaitGroupContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var wg parallel.WaitGroup
+	wg.Go(func() {
+		time.Sleep(time.Second)
+	})
+
+	if err := wg.Wait(ctx); err != context.DeadlineExceeded {
+		t.Error("require timeout", err)
+	}
+}
+
+func TestWaitGroupEmpty(t *testing.T) {
+	var wg parallel.WaitGroup
+	if err := wg.Wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitGroupReuse(t *testing.T) {
+	var wg parallel.WaitGroup
+	wg.Go(func() {
+		panic("hello")
+	})
+	if err := wg.Wait(context.Background()); err == nil {
+		t.Error("require panic error")
+	}
+
+	wg.Go(func() {})
+	if err := wg.Wait(context.Background()); err != nil {
+		t.Error("require no error after the panic was reported", err)
+	}
+}
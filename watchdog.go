@@ -0,0 +1,113 @@
+package parallel
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+//Diagnostics is the state of a loop passed to the function of [WithWatchdog]
+type Diagnostics struct {
+	//RunID is the [RunID] of the loop
+	RunID string
+
+	//Elapsed is the time since the loop started
+	Elapsed time.Duration
+
+	//Total is the number of iterations of the loop
+	Total int
+
+	//Finished is the number of iterations that are finished
+	Finished int
+
+	//Queued is the number of iterations that have not started yet
+	Queued int
+
+	//Workers is the number of workers of a loop with [WithWorkers], [WithClaiming] or [WithSequential].
+	//it is 0 if each iteration runs in its own goroutine
+	Workers int
+
+	//Running is the iterations that are running, longest running first.
+	//a worker runs at most one iteration at a time,
+	//so the workers that are not in Running are idle
+	Running []RunningIteration
+}
+
+//RunningIteration is an iteration that has not finished yet
+type RunningIteration struct {
+	//Index is the index of the iteration
+	Index int
+
+	//Worker is the [Meta.Worker] that runs the iteration.
+	//it is -1 if the iteration runs in its own goroutine
+	Worker int
+
+	//Start is the time when the iteration started
+	Start time.Time
+
+	//Elapsed is the time since the iteration started
+	Elapsed time.Duration
+}
+
+type watchdog struct {
+	expected time.Duration
+	onTrip   func(Diagnostics)
+}
+
+//WithWatchdog calls onTrip once with the diagnostics of the loop
+//if the loop is still running after expected.
+//onTrip is called in another goroutine and does not stop the loop
+//
+// parallel.ForWithOptions(ctx, 0, len(s), f, parallel.WithWatchdog(time.Minute, func(d parallel.Diagnostics) {
+// 		log.Println("slow loop", d.Elapsed, d.Running)
+// }))
+func WithWatchdog(expected time.Duration, onTrip func(diag Diagnostics)) Option {
+	return func(c *config) {
+		c.watchdog = &watchdog{expected: expected, onTrip: onTrip}
+	}
+}
+
+//watch starts to watch l and returns the function that stops watching
+func (w *watchdog) watch(l *loop) func() {
+	timer := time.AfterFunc(w.expected, func() {
+		w.onTrip(l.diagnostics())
+	})
+	return func() {
+		timer.Stop()
+	}
+}
+
+//diagnostics returns the current state of l
+func (l *loop) diagnostics() Diagnostics {
+	now := time.Now()
+	total := l.len()
+	d := Diagnostics{
+		RunID:    l.runID,
+		Elapsed:  now.Sub(l.start),
+		Total:    total,
+		Finished: int(atomic.LoadInt64(&l.finished)),
+		Queued:   total - int(atomic.LoadInt64(&l.started)),
+	}
+	if l.claiming {
+		d.Workers = workerCount(l.workers)
+		if d.Workers > total {
+			d.Workers = total
+		}
+	}
+
+	activeIterations.Range(func(_, v interface{}) bool {
+		if it := v.(*activeIteration); it.loop == l {
+			d.Running = append(d.Running, RunningIteration{
+				Index:   it.index,
+				Worker:  it.worker,
+				Start:   it.start,
+				Elapsed: now.Sub(it.start),
+			})
+		}
+		return true
+	})
+	sort.Slice(d.Running, func(i, j int) bool {
+		return d.Running[i].Elapsed > d.Running[j].Elapsed
+	})
+	return d
+}
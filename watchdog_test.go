@@ -0,0 +1,65 @@
+package parallel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestWatchdogTrip(t *testing.T) {
+	trips := make(chan parallel.Diagnostics, 1)
+	parallel.ForWithOptions(context.Background(), 0, 4, func(i int) {
+		if i == 2 {
+			time.Sleep(300 * time.Millisecond)
+		}
+	}, parallel.WithWatchdog(100*time.Millisecond, func(d parallel.Diagnostics) {
+		trips <- d
+	}))
+
+	select {
+	case d := <-trips:
+		if d.Total != 4 || d.Finished != 3 || d.Queued != 0 {
+			t.Error("bad diagnostics", d)
+		}
+		if len(d.Running) != 1 || d.Running[0].Index != 2 {
+			t.Error("require running iteration 2", d.Running)
+		}
+	default:
+		t.Error("require trip")
+	}
+}
+
+func TestWatchdogWorkers(t *testing.T) {
+	trips := make(chan parallel.Diagnostics, 1)
+	begin := time.Now()
+	parallel.ForWithOptions(context.Background(), 0, 4, func(i int) {
+		if i == 0 {
+			time.Sleep(300 * time.Millisecond)
+		}
+	}, parallel.WithWorkers(2), parallel.WithWatchdog(100*time.Millisecond, func(d parallel.Diagnostics) {
+		trips <- d
+	}))
+
+	select {
+	case d := <-trips:
+		if d.Workers != 2 || len(d.Running) != 1 {
+			t.Fatal("bad diagnostics", d)
+		}
+		it := d.Running[0]
+		if it.Index != 0 || (it.Worker != 0 && it.Worker != 1) || it.Start.Before(begin) {
+			t.Error("bad running iteration", it)
+		}
+	default:
+		t.Error("require trip")
+	}
+}
+
+func TestWatchdogNoTrip(t *testing.T) {
+	parallel.ForWithOptions(context.Background(), 0, 4, func(i int) {
+	}, parallel.WithWatchdog(100*time.Millisecond, func(d parallel.Diagnostics) {
+		t.Error("fast loop must not trip", d)
+	}))
+	time.Sleep(200 * time.Millisecond)
+}
@@ -0,0 +1,101 @@
+package parallel
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+//OrderedWriter writes the outputs of iterations to an io.Writer in the order of index.
+//each iteration writes to its own part, and a part is written to the underlying writer
+//when it is closed and all parts before it are written
+//
+// ow := parallel.NewOrderedWriter(file)
+// parallel.For(0, len(s), func(i int) {
+// 		part := ow.Part(i)
+// 		defer part.Close()
+// 		fmt.Fprintln(part, s[i])
+// })
+type OrderedWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	next   int
+	closed map[int]*bytes.Buffer
+	err    error
+}
+
+//NewOrderedWriter creates an OrderedWriter that writes to w.
+//indices start at 0
+func NewOrderedWriter(w io.Writer) *OrderedWriter {
+	return &OrderedWriter{w: w, closed: make(map[int]*bytes.Buffer)}
+}
+
+//Part returns the writer of the index i.
+//it must be closed once, even if nothing is written
+func (o *OrderedWriter) Part(i int) io.WriteCloser {
+	return &orderedPart{o: o, index: i}
+}
+
+//Err returns the first error of writing to the underlying writer
+func (o *OrderedWriter) Err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.err
+}
+
+//Pending returns the number of closed parts
+//that wait for a part before them
+func (o *OrderedWriter) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.closed)
+}
+
+var errPartClosed = errors.New("parallel: part closed")
+
+//close stores buf as the content of part i and writes the parts that are ready
+func (o *OrderedWriter) close(i int, buf *bytes.Buffer) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.closed[i]; ok || i < o.next {
+		return errPartClosed
+	}
+	o.closed[i] = buf
+
+	for {
+		b, ok := o.closed[o.next]
+		if !ok {
+			break
+		}
+		delete(o.closed, o.next)
+		o.next++
+		if o.err == nil {
+			_, o.err = b.WriteTo(o.w)
+		}
+	}
+	return o.err
+}
+
+type orderedPart struct {
+	o      *OrderedWriter
+	index  int
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (p *orderedPart) Write(b []byte) (int, error) {
+	if p.closed {
+		return 0, errPartClosed
+	}
+	return p.buf.Write(b)
+}
+
+func (p *orderedPart) Close() error {
+	if p.closed {
+		return errPartClosed
+	}
+	p.closed = true
+	return p.o.close(p.index, &p.buf)
+}
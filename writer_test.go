@@ -0,0 +1,52 @@
+package parallel_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestOrderedWriter(t *testing.T) {
+	var out bytes.Buffer
+	ow := parallel.NewOrderedWriter(&out)
+	parallel.For(0, 10, func(i int) {
+		part := ow.Part(i)
+		defer part.Close()
+
+		time.Sleep(time.Duration(10-i) * 5 * time.Millisecond)
+		fmt.Fprintf(part, "%d,", i)
+	})
+
+	if out.String() != "0,1,2,3,4,5,6,7,8,9," {
+		t.Error("require in order", out.String())
+	}
+	if ow.Err() != nil || ow.Pending() != 0 {
+		t.Error("bad state", ow.Err(), ow.Pending())
+	}
+}
+
+func TestOrderedWriterWaitPrefix(t *testing.T) {
+	var out bytes.Buffer
+	ow := parallel.NewOrderedWriter(&out)
+
+	p1 := ow.Part(1)
+	p1.Write([]byte("b"))
+	p1.Close()
+	if out.Len() != 0 || ow.Pending() != 1 {
+		t.Error("part 1 must wait for part 0", out.String())
+	}
+
+	p0 := ow.Part(0)
+	p0.Write([]byte("a"))
+	p0.Close()
+	if out.String() != "ab" {
+		t.Error("require ab", out.String())
+	}
+
+	if err := p0.Close(); err == nil {
+		t.Error("require closed error")
+	}
+}
@@ -0,0 +1,19 @@
+package parallel
+
+import (
+	"fmt"
+)
+
+//ForEachZipOf loops two slices of the same length together in parallel.
+//it is [ForEachZip] without reflection.
+//if the lengths are different, it returns ErrLengthMismatch and f is not called
+func ForEachZipOf[A, B any](a []A, b []B, f func(i int, ea A, eb B), opts ...Option) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%w: %d and %d", ErrLengthMismatch, len(a), len(b))
+	}
+
+	forRange(emptyContext, len(a), func(i int) {
+		f(i, a[i], b[i])
+	}, newConfig(opts))
+	return nil
+}
@@ -0,0 +1,29 @@
+package parallel_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rudty/go-parallel"
+)
+
+func TestForEachZipOf(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := []int{10, 20, 30}
+	sums := make([]int, len(a))
+	if err := parallel.ForEachZipOf(a, b, func(i int, x int, y int) {
+		sums[i] = x + y
+	}); err != nil {
+		t.Error(err)
+	}
+	if sums[0] != 11 || sums[1] != 22 || sums[2] != 33 {
+		t.Error("require [11 22 33]", sums)
+	}
+
+	err := parallel.ForEachZipOf(a, b[:2], func(i int, x int, y int) {
+		t.Error("must not be called")
+	})
+	if !errors.Is(err, parallel.ErrLengthMismatch) {
+		t.Error("require length mismatch", err)
+	}
+}